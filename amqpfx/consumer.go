@@ -8,6 +8,8 @@ import (
 	"github.com/nano-interactive/go-amqp/v3/consumer"
 	"github.com/rabbitmq/amqp091-go"
 	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/amqpmetrics"
 )
 
 func ConsumerModuleFunc[T consumer.Message](
@@ -16,34 +18,39 @@ func ConsumerModuleFunc[T consumer.Message](
 	connectionOptions connection.Config,
 	options ...consumer.Option[T],
 ) fx.Option {
-	create := func(opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
-		return consumer.NewFunc(handler, connectionOptions, queueOptions, opts...)
+	create := func(metrics *amqpmetrics.Metrics, opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
+		return consumer.NewFunc(observe(metrics, queueOptions.QueueName, handler), connectionOptions, queueOptions, opts...)
 	}
 
 	return c(queueOptions, connectionOptions, create, options...)
 }
 
+// ConsumerModuleRaw consumes deliveries with the handler, restoring the request ID
+// and traceparent of each delivery into the handler context (see ContextFromDelivery)
 func ConsumerModuleRaw[T consumer.Message](
 	handler consumer.RawHandler,
 	queueOptions consumer.QueueDeclare,
 	connectionOptions connection.Config,
 	options ...consumer.Option[T],
 ) fx.Option {
-	create := func(opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
-		return consumer.NewRaw(handler, connectionOptions, queueOptions, opts...)
+	create := func(metrics *amqpmetrics.Metrics, opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
+		observed := consumer.RawHandlerFunc(observe(metrics, queueOptions.QueueName, handler.Handle))
+		return consumer.NewRaw(CorrelatedRawHandler(observed), connectionOptions, queueOptions, opts...)
 	}
 
 	return c(queueOptions, connectionOptions, create, options...)
 }
 
+// ConsumerModuleRawFunc is like ConsumerModuleRaw for a handler function
 func ConsumerModuleRawFunc[T consumer.Message](
 	handler func(context.Context, *amqp091.Delivery) error,
 	queueOptions consumer.QueueDeclare,
 	connectionOptions connection.Config,
 	options ...consumer.Option[T],
 ) fx.Option {
-	create := func(opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
-		return consumer.NewRawFunc(handler, connectionOptions, queueOptions, opts...)
+	create := func(metrics *amqpmetrics.Metrics, opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
+		observed := consumer.RawHandlerFunc(observe(metrics, queueOptions.QueueName, handler))
+		return consumer.NewRawFunc(CorrelatedRawHandler(observed), connectionOptions, queueOptions, opts...)
 	}
 
 	return c(queueOptions, connectionOptions, create, options...)
@@ -55,8 +62,9 @@ func ConsumerModule[T consumer.Message](
 	connectionOptions connection.Config,
 	options ...consumer.Option[T],
 ) fx.Option {
-	create := func(opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
-		return consumer.New(handler, connectionOptions, queueOptions, opts...)
+	create := func(metrics *amqpmetrics.Metrics, opts ...consumer.Option[T]) (consumer.Consumer[T], error) {
+		observed := consumer.HandlerFunc[T](observe(metrics, queueOptions.QueueName, handler.Handle))
+		return consumer.New(observed, connectionOptions, queueOptions, opts...)
 	}
 
 	return c(queueOptions, connectionOptions, create, options...)
@@ -65,7 +73,7 @@ func ConsumerModule[T consumer.Message](
 func c[T consumer.Message](
 	queueOptions consumer.QueueDeclare,
 	connectionOptions connection.Config,
-	createConsumer func(...consumer.Option[T]) (consumer.Consumer[T], error),
+	createConsumer func(*amqpmetrics.Metrics, ...consumer.Option[T]) (consumer.Consumer[T], error),
 	options ...consumer.Option[T],
 ) fx.Option {
 	module := fmt.Sprintf("amqp-consumer-module-%s-%s", queueOptions.QueueName, connectionOptions.ConnectionName)
@@ -73,17 +81,17 @@ func c[T consumer.Message](
 
 	return fx.Module(
 		module,
-		fx.Provide(fx.Annotate(func() (consumer.Consumer[T], error) {
+		fx.Provide(fx.Annotate(func(metrics *amqpmetrics.Metrics) (consumer.Consumer[T], error) {
 			opts := make([]consumer.Option[T], 0, len(options)+1)
 			opts = append(opts, options...)
 
-			c, err := createConsumer(opts...)
+			c, err := createConsumer(metrics, opts...)
 			if err != nil {
 				return consumer.Consumer[T]{}, err
 			}
 
 			return c, nil
-		}, fx.ResultTags(`name:"`+name+`"`))),
+		}, fx.ParamTags(`optional:"true"`), fx.ResultTags(`name:"`+name+`"`))),
 		fx.Invoke(fx.Annotate(func(lc fx.Lifecycle, c consumer.Consumer[T]) {
 			lc.Append(fx.StartStopHook(
 				func(ctx context.Context) {
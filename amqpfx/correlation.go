@@ -0,0 +1,75 @@
+package amqpfx
+
+import (
+	"context"
+
+	"github.com/nano-interactive/go-amqp/v3/consumer"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx/tracing"
+)
+
+// Headers returns the AMQP headers carrying the request ID and traceparent of
+// the context, the traceparent of the span in the context takes precedence.
+// Set them on amqp091.Publishing when publishing on a channel, the publisher
+// of go-amqp does not support headers.
+func Headers(ctx context.Context) amqp091.Table {
+	headers := make(amqp091.Table, 2)
+
+	if id := correlation.RequestID(ctx); id != "" {
+		headers[correlation.HeaderRequestID] = id
+	}
+
+	if traceParent := correlation.TraceParent(ctx); traceParent != "" {
+		headers[correlation.HeaderTraceParent] = traceParent
+	}
+
+	tracing.Propagator().Inject(ctx, HeadersCarrier(headers))
+
+	return headers
+}
+
+// ContextFromDelivery restores the request ID and traceparent of the delivery
+// into the context, falling back to the correlation ID of the message. Missing
+// values are generated, and a logger with both is attached to the context.
+// The remote span context of the traceparent is stored in the context as well.
+func ContextFromDelivery(ctx context.Context, delivery *amqp091.Delivery) context.Context {
+	ctx = tracing.Propagator().Extract(ctx, HeadersCarrier(delivery.Headers))
+
+	return restoreDelivery(ctx, delivery, headerString(delivery.Headers, correlation.HeaderTraceParent))
+}
+
+// CorrelatedRawHandler restores the correlation of each delivery into the
+// context passed to the handler, see ContextFromDelivery. The handler runs in
+// a consumer span of the global TracerProvider, set by tracingfx.Module,
+// continuing the trace of the message.
+func CorrelatedRawHandler(handler consumer.RawHandler) consumer.RawHandlerFunc {
+	return consumer.RawHandlerFunc(func(ctx context.Context, delivery *amqp091.Delivery) (err error) {
+		ctx, span := startConsumerSpan(ctx, delivery)
+		defer func() { endConsumerSpan(span, err) }()
+
+		traceParent := tracing.TraceParent(span.SpanContext())
+		if traceParent == "" {
+			traceParent = headerString(delivery.Headers, correlation.HeaderTraceParent)
+		}
+
+		return handler.Handle(restoreDelivery(ctx, delivery, traceParent), delivery)
+	})
+}
+
+func restoreDelivery(ctx context.Context, delivery *amqp091.Delivery, traceParent string) context.Context {
+	requestID := headerString(delivery.Headers, correlation.HeaderRequestID)
+	if requestID == "" {
+		requestID = delivery.CorrelationId
+	}
+
+	return correlation.Restore(ctx, requestID, traceParent, log.Logger)
+}
+
+func headerString(headers amqp091.Table, key string) string {
+	value, _ := headers[key].(string)
+
+	return value
+}
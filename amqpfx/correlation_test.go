@@ -0,0 +1,93 @@
+package amqpfx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nano-interactive/go-amqp/v3/consumer"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/amqpfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx"
+)
+
+func TestCorrelationPropagation(t *testing.T) {
+	t.Parallel()
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	ctx := correlation.WithTraceParent(correlation.WithRequestID(context.Background(), "request-1"), traceParent)
+	headers := amqpfx.Headers(ctx)
+
+	require.Equal(t, amqp091.Table{
+		correlation.HeaderRequestID:   "request-1",
+		correlation.HeaderTraceParent: traceParent,
+	}, headers)
+	require.NoError(t, headers.Validate())
+
+	var restored context.Context
+
+	handler := amqpfx.CorrelatedRawHandler(consumer.RawHandlerFunc(func(ctx context.Context, _ *amqp091.Delivery) error {
+		restored = ctx
+		return nil
+	}))
+
+	require.NoError(t, handler.Handle(context.Background(), &amqp091.Delivery{Headers: headers}))
+	require.Equal(t, "request-1", correlation.RequestID(restored))
+	require.Equal(t, traceParent, correlation.TraceParent(restored))
+
+	// The correlation ID is used when the message has no request ID header
+	require.NoError(t, handler.Handle(context.Background(), &amqp091.Delivery{CorrelationId: "correlation-1"}))
+	require.Equal(t, "correlation-1", correlation.RequestID(restored))
+	require.True(t, correlation.ValidTraceParent(correlation.TraceParent(restored)))
+}
+
+func TestTracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "publish")
+	headers := amqpfx.Headers(ctx)
+	parent.End()
+
+	// The span of the context is the parent of the consumer span
+	require.Equal(t, tracingfx.TraceParent(parent.SpanContext()), headers[correlation.HeaderTraceParent])
+
+	var restored context.Context
+
+	handler := amqpfx.CorrelatedRawHandler(consumer.RawHandlerFunc(func(ctx context.Context, _ *amqp091.Delivery) error {
+		restored = ctx
+		return errors.New("failed")
+	}))
+
+	require.Error(t, handler.Handle(context.Background(), &amqp091.Delivery{
+		Headers:    headers,
+		Exchange:   "users",
+		RoutingKey: "users.created",
+	}))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	span := spans[1]
+	require.Equal(t, "process users", span.Name)
+	require.Equal(t, trace.SpanKindConsumer, span.SpanKind)
+	require.Equal(t, parent.SpanContext().TraceID(), span.SpanContext.TraceID())
+	require.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	require.Equal(t, codes.Error, span.Status.Code)
+
+	// Logs of the handler carry the trace ID of the consumer span
+	require.Equal(t, tracingfx.TraceParent(span.SpanContext), correlation.TraceParent(restored))
+}
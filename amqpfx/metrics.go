@@ -0,0 +1,39 @@
+package amqpfx
+
+import (
+	"context"
+	"time"
+
+	"github.com/nano-interactive/go-amqp/v3/publisher"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/amqpmetrics"
+)
+
+// observedPublisher counts the messages published to the exchange
+type observedPublisher[T any] struct {
+	pub      publisher.Pub[T]
+	metrics  *amqpmetrics.Metrics
+	exchange string
+}
+
+func (p observedPublisher[T]) Publish(ctx context.Context, msg T, config ...publisher.PublishConfig) error {
+	err := p.pub.Publish(ctx, msg, config...)
+	p.metrics.ObservePublish(p.exchange, err)
+
+	return err
+}
+
+// observe records the messages of the queue processed by handle
+func observe[T any](metrics *amqpmetrics.Metrics, queue string, handle func(context.Context, T) error) func(context.Context, T) error {
+	if metrics == nil {
+		return handle
+	}
+
+	return func(ctx context.Context, msg T) error {
+		start := time.Now()
+		err := handle(ctx, msg)
+		metrics.ObserveConsume(queue, start, err)
+
+		return err
+	}
+}
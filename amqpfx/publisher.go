@@ -7,6 +7,8 @@ import (
 	"github.com/nano-interactive/go-amqp/v3/connection"
 	"github.com/nano-interactive/go-amqp/v3/publisher"
 	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/amqpmetrics"
 )
 
 func GetPublisherName(connectionName, exchangeName string) string {
@@ -17,6 +19,9 @@ func GetPublisherParamName(connectionName, exchangeName string) string {
 	return fmt.Sprintf(`name:"amqp-publisher-param-%s-%s"`, exchangeName, connectionName)
 }
 
+// PublisherModule provides a publisher.Pub[T] publishing to the exchange.
+// Published messages and errors are counted when amqpmetrics.Metrics is
+// provided, e.g. by metricsfx.Module.
 func PublisherModule[T any](
 	connectionOptions connection.Config,
 	exchangeName string,
@@ -24,7 +29,7 @@ func PublisherModule[T any](
 ) fx.Option {
 	module := fmt.Sprintf("amqp-publisher-module-%s-%s", exchangeName, connectionOptions.ConnectionName)
 
-	return fx.Module(module, fx.Provide(fx.Annotate(func(lc fx.Lifecycle) (*publisher.Publisher[T], error) {
+	return fx.Module(module, fx.Provide(fx.Annotate(func(lc fx.Lifecycle, metrics *amqpmetrics.Metrics) (publisher.Pub[T], error) {
 		ctx, cancel := context.WithCancel(context.Background())
 
 		pub, err := publisher.New(ctx, connectionOptions, exchangeName, options...)
@@ -38,10 +43,14 @@ func PublisherModule[T any](
 			return pub.CloseWithContext(ctx)
 		}))
 
-		return pub, err
+		if metrics != nil {
+			return observedPublisher[T]{pub: pub, metrics: metrics, exchange: exchangeName}, nil
+		}
+
+		return pub, nil
 	},
+		fx.ParamTags(``, `optional:"true"`),
 		fx.ResultTags(
 			GetPublisherParamName(connectionOptions.ConnectionName, exchangeName)),
-		fx.As(new(publisher.Pub[T])),
 	)))
 }
@@ -0,0 +1,65 @@
+package amqpfx
+
+import (
+	"context"
+
+	"github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx/tracing"
+)
+
+// HeadersCarrier adapts AMQP headers to propagation.TextMapCarrier
+type HeadersCarrier amqp091.Table
+
+func (h HeadersCarrier) Get(key string) string {
+	return headerString(amqp091.Table(h), key)
+}
+
+func (h HeadersCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h HeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+
+	for key := range h {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// startConsumerSpan starts a span processing the delivery with the global
+// TracerProvider, set by tracingfx.Module, continuing the trace of its headers
+func startConsumerSpan(ctx context.Context, delivery *amqp091.Delivery) (context.Context, trace.Span) {
+	ctx = tracing.Propagator().Extract(ctx, HeadersCarrier(delivery.Headers))
+
+	destination := delivery.Exchange
+	if destination == "" {
+		destination = delivery.RoutingKey
+	}
+
+	return otel.Tracer(tracing.InstrumentationName).Start(ctx, "process "+destination,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(destination),
+			semconv.MessagingRabbitmqDestinationRoutingKey(delivery.RoutingKey),
+			semconv.MessagingMessageID(delivery.MessageId),
+		),
+	)
+}
+
+func endConsumerSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -1,49 +1,220 @@
 package configfx
 
 import (
-	utilsconfig "github.com/nano-interactive/go-utils/v2/config"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
 )
 
-func New[T any](appName string, paths ...string) (T, error) {
-	ps := []string{
-		"$XDG_CONFIG_HOME/" + appName,
-		"/etc/" + appName,
-		".",
+type (
+	// Option configures how the configuration is loaded
+	Option func(*loadOptions)
+
+	loadOptions struct {
+		dump         io.Writer
+		keys         map[string]string
+		resolvers    map[string]SecretResolver
+		sources      []Source
+		envPrefix    string
+		envSeparator string
+		name         string
+		environment  string
+		configFile   string
+		paths        []string
+		optionalFile bool
+		env          bool
+	}
+)
+
+// WithPaths adds directories searched for the config file
+func WithPaths(paths ...string) Option {
+	return func(opts *loadOptions) {
+		opts.paths = append(opts.paths, paths...)
+	}
+}
+
+// WithEnvPrefix sets the prefix of environment variables, the upper case
+// application name by default. An empty prefix binds variables without one.
+func WithEnvPrefix(prefix string) Option {
+	return func(opts *loadOptions) {
+		opts.envPrefix = prefix
+	}
+}
+
+// WithEnvSeparator sets the separator of environment variable names,
+// DefaultEnvSeparator by default. A separator like "__" keeps keys
+// containing "_" unambiguous.
+func WithEnvSeparator(separator string) Option {
+	return func(opts *loadOptions) {
+		opts.envSeparator = separator
+	}
+}
+
+// WithoutEnv disables environment variable overrides
+func WithoutEnv() Option {
+	return func(opts *loadOptions) {
+		opts.env = false
+	}
+}
+
+// WithOptionalFile loads the configuration from the environment alone when no
+// config file is found
+func WithOptionalFile() Option {
+	return func(opts *loadOptions) {
+		opts.optionalFile = true
+	}
+}
+
+// WithName sets the name of the config file without extension, "config" by default
+func WithName(name string) Option {
+	return func(opts *loadOptions) {
+		opts.name = name
+	}
+}
+
+// WithEnvironment merges the overlay of the environment over the config file,
+// e.g. config.production.yaml next to config.yaml for "production"
+func WithEnvironment(environment string) Option {
+	return func(opts *loadOptions) {
+		opts.environment = environment
+	}
+}
+
+// WithConfigFile loads the file instead of searching the paths. It takes
+// precedence over the --config flag.
+func WithConfigFile(file string) Option {
+	return func(opts *loadOptions) {
+		opts.configFile = file
+	}
+}
+
+// WithArgs reads the --config flag from args instead of os.Args, see ConfigFlag
+func WithArgs(args []string) Option {
+	return func(opts *loadOptions) {
+		opts.configFile = ConfigFlag(args)
+	}
+}
+
+// WithSourceDump writes every key of the loaded configuration with its value
+// and the source that set it: a file, an environment variable or a default
+func WithSourceDump(w io.Writer) Option {
+	return func(opts *loadOptions) {
+		opts.dump = w
 	}
+}
 
-	if len(paths) > 0 {
-		ps = append(ps, paths...)
+// WithSecretResolver resolves the secret references ${scheme:reference} of the
+// scheme with r, e.g. WithSecretResolver("vault", VaultResolver(reader)).
+// The "file" and "env" schemes are resolved by default.
+func WithSecretResolver(scheme string, r SecretResolver) Option {
+	return func(opts *loadOptions) {
+		opts.resolvers[scheme] = r
 	}
+}
 
-	v, err := utilsconfig.NewWithModifier(utilsconfig.Config{
-		ProjectName: appName,
-		Name:        "config",
-		Type:        "yaml",
-		Paths:       ps,
-	})
+// New loads config.yaml from $XDG_CONFIG_HOME/<appName>, /etc/<appName>, the
+// working directory and paths, overridden by environment variables, see Load.
+func New[T any](appName string, paths ...string) (T, error) {
+	return Load[T](appName, WithPaths(paths...))
+}
+
+// Load loads the configuration from layered sources, each overriding the
+// previous ones:
+//
+//   - the config file: the --config flag, or the first config.{yaml,yml,toml,json,env}
+//     found in $XDG_CONFIG_HOME/<appName>, /etc/<appName>, the working directory
+//     and the paths of WithPaths
+//   - the files of the config.d directory next to it, in lexical order
+//   - the overlay of WithEnvironment, e.g. config.production.yaml
+//   - the values of the remote sources of WithRemoteSource, e.g. Consul KV
+//   - environment variables named after the keys, e.g. MYAPP_DATABASE_HOST for
+//     the field "database.host" of the application "myapp" (see BindEnv)
+//
+// Variables of dotenv files are named like environment variables, with or
+// without the prefix.
+//
+// Secret references in string values are resolved before unmarshalling, e.g.
+// ${file:/run/secrets/db_password} or ${env:DB_PASS}, see WithSecretResolver.
+func Load[T any](appName string, options ...Option) (T, error) {
+	opts := newOptions(appName, options)
+
+	l, err := loadLayers[T](opts)
 	if err != nil {
-		var t T
-		return t, err
+		var c T
+		return c, err
+	}
+
+	c, err := NewWithViper[T](l.v)
+	if err != nil {
+		return c, err
 	}
 
-	return NewWithViper[T](v)
+	if opts.dump != nil {
+		if err := l.dump(opts.dump); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
 }
 
+// NewWithViper unmarshals the configuration of v into T. The "default" tags of
+// T are applied first (see SetDefaults), then every key tagged
+// `required:"true"` must be set (see CheckRequired). When T implements
+// validation.Validatable, the configuration is validated as well.
 func NewWithViper[T any](v *viper.Viper) (T, error) {
 	var c T
 
+	SetDefaults[T](v)
+
+	if err := CheckRequired[T](v); err != nil {
+		return c, fmt.Errorf("configfx: %w", err)
+	}
+
 	if err := v.Unmarshal(&c); err != nil {
 		return c, err
 	}
 
+	if err := validate(&c); err != nil {
+		return c, fmt.Errorf("configfx: invalid configuration: %w", err)
+	}
+
 	return c, nil
 }
 
+// Module supplies cfg. Add Fields[T]() to provide its nested struct fields as
+// well.
 func Module[T any](cfg T) fx.Option {
 	return fx.Module(
 		"config",
 		fx.Supply(cfg),
 	)
 }
+
+func newOptions(appName string, options []Option) loadOptions {
+	opts := loadOptions{
+		paths: []string{
+			"$XDG_CONFIG_HOME/" + appName,
+			"/etc/" + appName,
+			".",
+		},
+		name:         "config",
+		configFile:   ConfigFlag(os.Args[1:]),
+		envPrefix:    envPrefix(appName),
+		envSeparator: DefaultEnvSeparator,
+		env:          true,
+		resolvers: map[string]SecretResolver{
+			"file": FileResolver(),
+			"env":  EnvResolver(),
+		},
+	}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return opts
+}
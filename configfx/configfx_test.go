@@ -0,0 +1,135 @@
+package configfx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+)
+
+type (
+	testDatabase struct {
+		Host    string        `mapstructure:"host"`
+		Timeout time.Duration `mapstructure:"connection_timeout"`
+		Port    uint16        `mapstructure:"port"`
+	}
+
+	CommonConfig struct {
+		Environment string `mapstructure:"environment"`
+	}
+
+	testConfig struct {
+		Database     *testDatabase     `mapstructure:"database"`
+		Labels       map[string]string `mapstructure:"labels"`
+		CommonConfig `mapstructure:",squash"`
+		Name         string   `mapstructure:"name"`
+		Ignored      string   `mapstructure:"-"`
+		Tags         []string `mapstructure:"tags"`
+		Replicas     int
+	}
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600))
+
+	return dir
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	dir := writeConfig(t, `
+name: from-file
+database:
+  host: localhost
+  port: 5432
+`)
+
+	t.Setenv("MY_APP_DATABASE_HOST", "db.internal")
+	t.Setenv("MY_APP_DATABASE_CONNECTION_TIMEOUT", "3s")
+	t.Setenv("MY_APP_ENVIRONMENT", "production")
+	t.Setenv("MY_APP_REPLICAS", "3")
+	t.Setenv("MY_APP_TAGS", "a,b")
+	t.Setenv("MY_APP_IGNORED", "ignored")
+
+	cfg, err := configfx.New[testConfig]("my-app", dir)
+	require.NoError(t, err)
+
+	require.Equal(t, "from-file", cfg.Name)
+	require.Equal(t, "db.internal", cfg.Database.Host)
+	require.Equal(t, 3*time.Second, cfg.Database.Timeout)
+	require.Equal(t, uint16(5432), cfg.Database.Port)
+	require.Equal(t, "production", cfg.Environment)
+	require.Equal(t, 3, cfg.Replicas)
+	require.Equal(t, []string{"a", "b"}, cfg.Tags)
+	require.Empty(t, cfg.Ignored)
+}
+
+func TestLoadEnvPrefixAndSeparator(t *testing.T) {
+	dir := writeConfig(t, "name: from-file\n")
+
+	t.Setenv("SVC__NAME", "from-env")
+	t.Setenv("SVC__DATABASE__CONNECTION_TIMEOUT", "1m")
+	t.Setenv("NAME", "without-prefix")
+
+	cfg, err := configfx.Load[testConfig]("my-app",
+		configfx.WithPaths(dir),
+		configfx.WithEnvPrefix("svc"),
+		configfx.WithEnvSeparator("__"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "from-env", cfg.Name)
+	require.Equal(t, time.Minute, cfg.Database.Timeout)
+
+	cfg, err = configfx.Load[testConfig]("my-app", configfx.WithPaths(dir), configfx.WithEnvPrefix(""))
+	require.NoError(t, err)
+	require.Equal(t, "without-prefix", cfg.Name)
+
+	cfg, err = configfx.Load[testConfig]("my-app", configfx.WithPaths(dir), configfx.WithoutEnv())
+	require.NoError(t, err)
+	require.Equal(t, "from-file", cfg.Name)
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv("MY_APP_NAME", "from-env")
+
+	_, err := configfx.Load[testConfig]("my-app", configfx.WithPaths(dir))
+	require.ErrorIs(t, err, configfx.ErrConfigFileNotFound)
+
+	t.Chdir(dir)
+
+	cfg, err := configfx.Load[testConfig]("my-app", configfx.WithOptionalFile())
+	require.NoError(t, err)
+	require.Equal(t, "from-env", cfg.Name)
+}
+
+func TestEnvName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "MYAPP_DATABASE_HOST", configfx.EnvName("myapp", "_", "database.host"))
+	require.Equal(t, "DATABASE__SSL_MODE", configfx.EnvName("", "__", "database.ssl_mode"))
+}
+
+type nodeConfig struct {
+	Next *nodeConfig `mapstructure:"next"`
+	Name string      `mapstructure:"name" default:"root" required:"true"`
+}
+
+func TestLoadSelfReferentialConfig(t *testing.T) {
+	dir := writeConfig(t, "next:\n  name: child\n")
+
+	t.Setenv("MY_APP_NAME", "from-env")
+
+	cfg, err := configfx.Load[nodeConfig]("my-app", configfx.WithPaths(dir))
+	require.NoError(t, err)
+	require.Equal(t, "from-env", cfg.Name)
+	require.Equal(t, "child", cfg.Next.Name)
+	require.Nil(t, cfg.Next.Next)
+}
@@ -0,0 +1,46 @@
+package configfx
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultEnvSeparator separates the prefix and the keys of nested fields in
+// environment variable names
+const DefaultEnvSeparator = "_"
+
+// BindEnv binds every field of T to an environment variable named after its
+// key path, e.g. the field "database.host" to MYAPP_DATABASE_HOST for the
+// prefix "MYAPP" and separator "_". Without a prefix the variable is
+// DATABASE_HOST. Environment variables take precedence over the config file.
+//
+// Unlike viper.AutomaticEnv, fields missing from the config file are bound
+// as well, so any value can be set through the environment.
+func BindEnv[T any](v *viper.Viper, prefix, separator string) error {
+	for _, f := range fields(reflect.TypeFor[T]()) {
+		if err := v.BindEnv(f.Path, EnvName(prefix, separator, f.Path)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnvName returns the environment variable of the key path
+func EnvName(prefix, separator, path string) string {
+	name := strings.ToUpper(strings.ReplaceAll(path, ".", separator))
+
+	if prefix == "" {
+		return name
+	}
+
+	return strings.ToUpper(prefix) + separator + name
+}
+
+// envPrefix derives the default prefix from the application name,
+// e.g. MY_APP for "my-app"
+func envPrefix(appName string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(appName))
+}
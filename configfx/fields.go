@@ -0,0 +1,129 @@
+package configfx
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// field is a leaf of the configuration struct, a value that is not decoded
+// field by field
+type field struct {
+	StructField reflect.StructField
+	// Path is the viper key of the field, e.g. "database.host"
+	Path string
+	// Index is the index sequence of the field for reflect.Value.FieldByIndex
+	Index []int
+}
+
+//nolint:gochecknoglobals
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// fields returns the leaves of the struct type t, named after their
+// mapstructure tags the way viper decodes them. Fields tagged "-" and
+// unexported fields are skipped, squashed structs are flattened into their
+// parent. A struct nested in itself, e.g. through a pointer, is not walked
+// again.
+func fields(t reflect.Type) []field {
+	var result []field
+
+	walkFields(t, "", nil, make(map[reflect.Type]struct{}), func(f field) {
+		result = append(result, f)
+	})
+
+	return result
+}
+
+// walkFields calls fn with the leaves of t, visiting holding the struct types
+// on the path to t
+func walkFields(t reflect.Type, prefix string, index []int, visiting map[reflect.Type]struct{}, fn func(field)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	if _, ok := visiting[t]; ok {
+		return
+	}
+
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, squash, skip := fieldName(sf)
+		if skip {
+			continue
+		}
+
+		path := prefix
+		if !squash {
+			path = joinPath(prefix, name)
+		}
+
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if isNested(sf.Type) {
+			walkFields(sf.Type, path, fieldIndex, visiting, fn)
+			continue
+		}
+
+		fn(field{StructField: sf, Path: path, Index: fieldIndex})
+	}
+}
+
+// fieldName returns the key of the field from its mapstructure tag, lower
+// case like viper keys
+func fieldName(sf reflect.StructField) (name string, squash, skip bool) {
+	tag := sf.Tag.Get("mapstructure")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "squash" {
+			squash = true
+		}
+	}
+
+	if name == "" {
+		name = sf.Name
+	}
+
+	return strings.ToLower(name), squash, false
+}
+
+// isNested reports whether values of the type are decoded field by field
+func isNested(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType || t == durationType {
+		return false
+	}
+
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
@@ -0,0 +1,365 @@
+package configfx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrConfigFileNotFound is returned when no config file is found in the
+	// search paths
+	ErrConfigFileNotFound = errors.New("config file not found")
+	// ErrConflictingKey is returned when a key is set both to a value and to
+	// nested keys, e.g. by the remote keys "database" and "database/host"
+	ErrConflictingKey = errors.New("conflicting keys")
+)
+
+// SourceDefault, SourceEnvPrefix and SourceRemotePrefix name the sources of
+// keys set by a default tag, by an environment variable and by a remote Source
+// in the source dump
+const (
+	SourceDefault      = "default"
+	SourceEnvPrefix    = "env "
+	SourceRemotePrefix = "remote "
+)
+
+// extensions maps the supported file extensions to their viper config types,
+// in the order they are searched
+//
+//nolint:gochecknoglobals
+var extensions = []struct {
+	ext, configType string
+}{
+	{"yaml", "yaml"},
+	{"yml", "yaml"},
+	{"toml", "toml"},
+	{"json", "json"},
+	{"env", "dotenv"},
+}
+
+// layers is the configuration merged from every source
+type layers struct {
+	v *viper.Viper
+	// sources maps every key to the source that set it last
+	sources map[string]string
+	// files are the files merged, in order
+	files []string
+	// secrets are the keys set from a secret reference
+	secrets map[string]struct{}
+	// fragments is the .d directory of the base file
+	fragments string
+}
+
+// loadLayers merges the base config file, the fragments of its .d directory,
+// the environment overlay and the remote sources, then binds the environment
+// variables of T and resolves the secret references
+func loadLayers[T any](opts loadOptions) (*layers, error) {
+	l := &layers{
+		v:       viper.New(),
+		sources: make(map[string]string),
+		secrets: make(map[string]struct{}),
+	}
+	opts.keys = envKeys(reflect.TypeFor[T](), opts)
+
+	base, err := opts.baseFile()
+	if err != nil && (!opts.optionalFile || !errors.Is(err, ErrConfigFileNotFound)) {
+		return nil, err
+	}
+
+	if base != "" {
+		l.fragments = strings.TrimSuffix(base, filepath.Ext(base)) + ".d"
+
+		fragments, err := configFiles(l.fragments)
+		if err != nil {
+			return nil, err
+		}
+
+		l.files = append(l.files, base)
+		l.files = append(l.files, fragments...)
+
+		if overlay := opts.overlayFile(base); overlay != "" {
+			l.files = append(l.files, overlay)
+		}
+
+		for _, file := range l.files {
+			if err := l.merge(file, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, source := range opts.sources {
+		if err := l.mergeRemote(source); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.env {
+		if err := BindEnv[T](l.v, opts.envPrefix, opts.envSeparator); err != nil {
+			return nil, err
+		}
+
+		for _, f := range fields(reflect.TypeFor[T]()) {
+			name := EnvName(opts.envPrefix, opts.envSeparator, f.Path)
+			if _, ok := os.LookupEnv(name); ok {
+				l.sources[f.Path] = SourceEnvPrefix + name
+			}
+		}
+	}
+
+	secrets, err := ResolveSecrets(l.v, opts.resolvers)
+	if err != nil {
+		return nil, fmt.Errorf("configfx: %w", err)
+	}
+
+	for _, key := range secrets {
+		l.secrets[key] = struct{}{}
+	}
+
+	return l, nil
+}
+
+// envKeys maps the environment variable names of the fields of t, with and
+// without the prefix, to their key paths
+func envKeys(t reflect.Type, opts loadOptions) map[string]string {
+	keys := make(map[string]string)
+
+	for _, f := range fields(t) {
+		keys[EnvName("", opts.envSeparator, f.Path)] = f.Path
+		keys[EnvName(opts.envPrefix, opts.envSeparator, f.Path)] = f.Path
+	}
+
+	return keys
+}
+
+// merge merges the config file into the configuration
+func (l *layers) merge(file string, opts loadOptions) error {
+	settings, keys, err := readConfigFile(file, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := l.v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("configfx: failed to merge %s: %w", file, err)
+	}
+
+	for _, key := range keys {
+		l.sources[key] = file
+	}
+
+	return nil
+}
+
+// dump writes every key of the configuration with its value and the source
+// that set it, sorted by key. The values of secrets are redacted.
+func (l *layers) dump(w io.Writer) error {
+	keys := l.v.AllKeys()
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		source, ok := l.sources[key]
+		if !ok {
+			source = SourceDefault
+		}
+
+		value := l.v.Get(key)
+		if _, ok := l.secrets[key]; ok {
+			value = Redacted
+		}
+
+		if _, err := fmt.Fprintf(w, "%s = %v  # %s\n", key, value, source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readConfigFile returns the nested settings of the config file and their keys
+func readConfigFile(file string, opts loadOptions) (map[string]any, []string, error) {
+	configType := configTypeOf(file)
+
+	fv := viper.New()
+	fv.SetConfigFile(file)
+	fv.SetConfigType(configType)
+
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, nil, fmt.Errorf("configfx: failed to read %s: %w", file, err)
+	}
+
+	if configType == "dotenv" {
+		settings, keys, err := opts.dotenvSettings(fv.AllSettings())
+		if err != nil {
+			return nil, nil, fmt.Errorf("configfx: failed to read %s: %w", file, err)
+		}
+
+		return settings, keys, nil
+	}
+
+	return fv.AllSettings(), fv.AllKeys(), nil
+}
+
+// baseFile returns the explicit config file, or the first config file found
+// in the search paths
+func (opts loadOptions) baseFile() (string, error) {
+	if opts.configFile != "" {
+		if _, err := os.Stat(opts.configFile); err != nil {
+			return "", fmt.Errorf("configfx: %w", err)
+		}
+
+		return opts.configFile, nil
+	}
+
+	for _, path := range opts.paths {
+		if file := findConfigFile(os.ExpandEnv(path), opts.name); file != "" {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("configfx: %w: %s in %s", ErrConfigFileNotFound, opts.name, strings.Join(opts.paths, ", "))
+}
+
+// overlayFile returns the config file of the environment next to the base
+// file, e.g. config.production.yaml for config.yaml
+func (opts loadOptions) overlayFile(base string) string {
+	if opts.environment == "" {
+		return ""
+	}
+
+	name := strings.TrimSuffix(filepath.Base(base), filepath.Ext(base)) + "." + opts.environment
+
+	return findConfigFile(filepath.Dir(base), name)
+}
+
+// dotenvSettings maps the variables of a dotenv file to the keys of their
+// environment variable names, e.g. DATABASE_HOST or MYAPP_DATABASE_HOST to
+// "database.host". Other variables are kept as is. The settings are returned
+// nested like the settings of the other formats, with the flat keys.
+func (opts loadOptions) dotenvSettings(settings map[string]any) (map[string]any, []string, error) {
+	result := make(map[string]any, len(settings))
+	keys := make([]string, 0, len(settings))
+
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		key, ok := opts.keys[strings.ToUpper(name)]
+		if !ok {
+			key = name
+		}
+
+		if err := setNested(result, key, settings[name]); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return result, keys, nil
+}
+
+// setNested sets the value of the key path nested under its parent keys, e.g.
+// {"database": {"host": value}} for "database.host". It returns
+// ErrConflictingKey when a parent key is set to a value, or the key to a
+// table of nested keys.
+func setNested(settings map[string]any, key string, value any) error {
+	parts := strings.Split(key, ".")
+	m := settings
+
+	for i, part := range parts[:len(parts)-1] {
+		existing, exists := m[part]
+
+		child, ok := existing.(map[string]any)
+		if exists && !ok {
+			return fmt.Errorf("%w: %s is a value", ErrConflictingKey, strings.Join(parts[:i+1], "."))
+		}
+
+		if !ok {
+			child = make(map[string]any)
+			m[part] = child
+		}
+
+		m = child
+	}
+
+	leaf := parts[len(parts)-1]
+	if _, ok := m[leaf].(map[string]any); ok {
+		return fmt.Errorf("%w: %s has nested keys", ErrConflictingKey, key)
+	}
+
+	m[leaf] = value
+
+	return nil
+}
+
+// ConfigFlag returns the value of the --config flag of the arguments,
+// accepting "--config path", "--config=path" and the single dash forms
+func ConfigFlag(args []string) string {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+
+		if hasValue {
+			return value
+		}
+
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
+
+func findConfigFile(dir, name string) string {
+	for _, e := range extensions {
+		file := filepath.Join(dir, name+"."+e.ext)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// configFiles returns the config files of the directory in lexical order
+func configFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("configfx: %w", err)
+	}
+
+	files := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() && configTypeOf(entry.Name()) != "" {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+func configTypeOf(file string) string {
+	ext := strings.TrimPrefix(filepath.Ext(file), ".")
+
+	for _, e := range extensions {
+		if e.ext == ext {
+			return e.configType
+		}
+	}
+
+	return ""
+}
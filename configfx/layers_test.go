@@ -0,0 +1,103 @@
+package configfx_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := writeConfig(t, `
+name: base
+replicas: 1
+database:
+  host: localhost
+  port: 5432
+`)
+
+	writeFile(t, filepath.Join(dir, "config.d", "20-name.toml"), "name = \"fragment-20\"\n")
+	writeFile(t, filepath.Join(dir, "config.d", "10-database.json"), `{"database": {"host": "db.internal"}, "name": "fragment-10"}`)
+	writeFile(t, filepath.Join(dir, "config.d", "README.md"), "not a config file")
+	writeFile(t, filepath.Join(dir, "config.production.env"), "MY_APP_REPLICAS=3\nDATABASE_PORT=6432\n")
+
+	t.Setenv("MY_APP_TAGS", "a,b")
+
+	var dump bytes.Buffer
+
+	cfg, err := configfx.Load[testConfig]("my-app",
+		configfx.WithPaths(dir),
+		configfx.WithEnvironment("production"),
+		configfx.WithSourceDump(&dump),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "fragment-20", cfg.Name)
+	require.Equal(t, "db.internal", cfg.Database.Host)
+	require.Equal(t, uint16(6432), cfg.Database.Port)
+	require.Equal(t, 3, cfg.Replicas)
+	require.Equal(t, []string{"a", "b"}, cfg.Tags)
+
+	out := dump.String()
+	require.Contains(t, out, "name = fragment-20  # "+filepath.Join(dir, "config.d", "20-name.toml")+"\n")
+	require.Contains(t, out, "database.host = db.internal  # "+filepath.Join(dir, "config.d", "10-database.json")+"\n")
+	require.Contains(t, out, "database.port = 6432  # "+filepath.Join(dir, "config.production.env")+"\n")
+	require.Contains(t, out, "tags = a,b  # env MY_APP_TAGS\n")
+
+	// Without the environment the overlay is ignored
+	cfg, err = configfx.Load[testConfig]("my-app", configfx.WithPaths(dir))
+	require.NoError(t, err)
+	require.Equal(t, 1, cfg.Replicas)
+	require.Equal(t, uint16(5432), cfg.Database.Port)
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "service.json")
+	writeFile(t, file, `{"name": "from-flag"}`)
+
+	cfg, err := configfx.Load[testConfig]("my-app", configfx.WithArgs([]string{"serve", "--config", file}))
+	require.NoError(t, err)
+	require.Equal(t, "from-flag", cfg.Name)
+
+	cfg, err = configfx.Load[testConfig]("my-app", configfx.WithConfigFile(file))
+	require.NoError(t, err)
+	require.Equal(t, "from-flag", cfg.Name)
+
+	_, err = configfx.Load[testConfig]("my-app", configfx.WithConfigFile(filepath.Join(dir, "missing.yaml")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expected string
+		args     []string
+	}{
+		"separate":    {args: []string{"--config", "a.yaml"}, expected: "a.yaml"},
+		"equals":      {args: []string{"serve", "--config=b.toml"}, expected: "b.toml"},
+		"single dash": {args: []string{"-config", "c.json"}, expected: "c.json"},
+		"missing":     {args: []string{"--verbose"}, expected: ""},
+		"no value":    {args: []string{"--config"}, expected: ""},
+		"other flag":  {args: []string{"--configuration=x"}, expected: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, configfx.ConfigFlag(tt.args))
+		})
+	}
+}
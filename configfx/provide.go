@@ -0,0 +1,102 @@
+package configfx
+
+import (
+	"reflect"
+
+	"go.uber.org/fx"
+)
+
+// Fields provides every nested struct field of T as its own value, built from
+// the T of the container, e.g. supplied by Module. A field tagged `fx:"name"`
+// is provided as a named value, `name:"name"`, and a field tagged `fx:"-"` is
+// not provided. Squashed structs are walked into, deeper fields are not
+// provided.
+//
+// Every unnamed field must have a distinct type that is not provided another
+// way, e.g. two databasesfx.PostgresConfig fields must be named.
+func Fields[T any]() fx.Option {
+	t := reflect.TypeFor[T]()
+
+	var providers []fx.Option
+
+	for _, sf := range nestedFields(t, nil, make(map[reflect.Type]struct{})) {
+		name := sf.Tag.Get("fx")
+		if name == "-" {
+			continue
+		}
+
+		var provider any = fieldProvider(t, sf)
+		if name != "" {
+			provider = fx.Annotate(provider, fx.ResultTags(`name:"`+name+`"`))
+		}
+
+		providers = append(providers, fx.Provide(provider))
+	}
+
+	return fx.Options(providers...)
+}
+
+// nestedFields returns the exported struct fields of the struct type t
+// decoded field by field, with the index sequence of the field from t.
+// visiting holds the squashed struct types on the path to t.
+func nestedFields(t reflect.Type, index []int, visiting map[reflect.Type]struct{}) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if _, ok := visiting[t]; ok || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	var result []reflect.StructField
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		_, squash, skip := fieldName(sf)
+		if skip || !isNested(sf.Type) {
+			continue
+		}
+
+		sf.Index = append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if squash {
+			result = append(result, nestedFields(sf.Type, sf.Index, visiting)...)
+			continue
+		}
+
+		result = append(result, sf)
+	}
+
+	return result
+}
+
+// fieldProvider returns a func(T) F returning the field of T, the zero F when
+// a pointer on the way to the field is nil
+func fieldProvider(t reflect.Type, sf reflect.StructField) any {
+	fnType := reflect.FuncOf([]reflect.Type{t}, []reflect.Type{sf.Type}, false)
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		v := args[0]
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Pointer {
+			return []reflect.Value{reflect.Zero(sf.Type)}
+		}
+
+		value, err := v.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(sf.Type)}
+		}
+
+		return []reflect.Value{value}
+	}).Interface()
+}
@@ -0,0 +1,157 @@
+package configfx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/databasesfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/loggerfx"
+)
+
+type (
+	SharedConfig struct {
+		Logger loggerfx.Sink `mapstructure:"logger"`
+	}
+
+	providedConfig struct {
+		Cache        *testDatabase `mapstructure:"cache"`
+		Primary      testDatabase  `mapstructure:"primary" fx:"primary"`
+		Replica      testDatabase  `mapstructure:"replica" fx:"replica"`
+		Ignored      testDatabase  `mapstructure:"ignored" fx:"-"`
+		SharedConfig `mapstructure:",squash"`
+		Database     databasesfx.PostgresConfig `mapstructure:"database"`
+		Name         string                     `mapstructure:"name"`
+	}
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	cfg := providedConfig{
+		Cache:        &testDatabase{Host: "cache"},
+		Primary:      testDatabase{Host: "primary"},
+		Replica:      testDatabase{Host: "replica"},
+		SharedConfig: SharedConfig{Logger: loggerfx.Sink{Type: loggerfx.Stdout}},
+		Database:     databasesfx.PostgresConfig{DBName: "app"},
+	}
+
+	var fields struct {
+		fx.In
+
+		Cache    *testDatabase
+		Primary  testDatabase `name:"primary"`
+		Replica  testDatabase `name:"replica"`
+		Logger   loggerfx.Sink
+		Database databasesfx.PostgresConfig
+	}
+
+	app := fxtest.New(t,
+		configfx.Module(cfg),
+		configfx.Fields[providedConfig](),
+		fx.Populate(&fields),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Equal(t, "cache", fields.Cache.Host)
+	require.Equal(t, "primary", fields.Primary.Host)
+	require.Equal(t, "replica", fields.Replica.Host)
+	require.Equal(t, loggerfx.Stdout, fields.Logger.Type)
+	require.Equal(t, "app", fields.Database.DBName)
+
+	// Ignored fields and fields that are not structs are not provided
+	err := fx.New(
+		configfx.Module(cfg),
+		configfx.Fields[providedConfig](),
+		fx.Invoke(func(testDatabase) {}),
+		fx.NopLogger,
+	).Err()
+	require.ErrorContains(t, err, "missing type: configfx_test.testDatabase")
+
+	err = fx.New(
+		configfx.Module(cfg),
+		configfx.Fields[providedConfig](),
+		fx.Invoke(func(string) {}),
+		fx.NopLogger,
+	).Err()
+	require.ErrorContains(t, err, "missing type: string")
+}
+
+func TestFieldsNilPointers(t *testing.T) {
+	t.Parallel()
+
+	var cache *testDatabase
+
+	app := fxtest.New(t,
+		configfx.Module(&providedConfig{}),
+		configfx.Fields[*providedConfig](),
+		fx.Populate(&cache),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Nil(t, cache)
+
+	app = fxtest.New(t,
+		configfx.Module[*providedConfig](nil),
+		configfx.Fields[*providedConfig](),
+		fx.Populate(&cache),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Nil(t, cache)
+}
+
+func TestModuleOnlySuppliesConfig(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Stdout   loggerfx.Sink              `mapstructure:"stdout"`
+		Stderr   loggerfx.Sink              `mapstructure:"stderr"`
+		Database databasesfx.PostgresConfig `mapstructure:"database"`
+	}
+
+	cfg := config{Database: databasesfx.PostgresConfig{DBName: "app"}}
+
+	var database databasesfx.PostgresConfig
+
+	// Fields of the same type and glue providers do not conflict with Module
+	app := fxtest.New(t,
+		configfx.Module(cfg),
+		fx.Provide(func(c config) databasesfx.PostgresConfig {
+			return c.Database
+		}),
+		fx.Populate(&database),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Equal(t, "app", database.DBName)
+}
+
+type squashedLoop struct {
+	*squashedLoop `mapstructure:",squash"`
+
+	Database testDatabase `mapstructure:"database"`
+}
+
+func TestFieldsSelfReferentialSquash(t *testing.T) {
+	t.Parallel()
+
+	var database testDatabase
+
+	app := fxtest.New(t,
+		configfx.Module(squashedLoop{Database: testDatabase{Host: "db"}}),
+		configfx.Fields[squashedLoop](),
+		fx.Populate(&database),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Equal(t, "db", database.Host)
+}
@@ -0,0 +1,165 @@
+package configfx
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteTimeout bounds loading the values of a remote Source
+const RemoteTimeout = 10 * time.Second
+
+type (
+	// Source is a key-value backend of configuration, e.g. Consul KV or etcd.
+	// Keys are slash separated key paths relative to the prefix of the
+	// application, e.g. "database/host" for the key "database.host" stored at
+	// "myapp/database/host". Values are decoded like the values of
+	// environment variables. configfx ships no backend client, applications
+	// implement Source with the client of their backend.
+	Source interface {
+		Values(ctx context.Context) (map[string]string, error)
+	}
+
+	// WatchableSource is a Source notifying changes of its values, used by
+	// WatchModule to reload the configuration
+	WatchableSource interface {
+		Source
+		// Watch starts watching the values in the background, calling
+		// onChange after every change until ctx is done. It returns once
+		// changes are watched, so no change made after it returns is missed.
+		Watch(ctx context.Context, onChange func()) error
+	}
+
+	// MemorySource is an in-process WatchableSource, e.g. to test the
+	// configuration of an application without a key-value backend
+	MemorySource struct {
+		values   map[string]string
+		watchers map[chan struct{}]struct{}
+		mu       sync.RWMutex
+	}
+)
+
+var _ WatchableSource = (*MemorySource)(nil)
+
+// WithRemoteSource merges the values of the source over the config files.
+// Environment variables still take precedence. Sources are merged in the
+// order of their options.
+func WithRemoteSource(source Source) Option {
+	return func(opts *loadOptions) {
+		opts.sources = append(opts.sources, source)
+	}
+}
+
+func NewMemorySource(values map[string]string) *MemorySource {
+	if values == nil {
+		values = make(map[string]string)
+	}
+
+	return &MemorySource{
+		values:   maps.Clone(values),
+		watchers: make(map[chan struct{}]struct{}),
+	}
+}
+
+func (s *MemorySource) Values(context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.values), nil
+}
+
+// Set sets the value of the key and notifies the watchers
+func (s *MemorySource) Set(key, value string) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Delete deletes the key and notifies the watchers
+func (s *MemorySource) Delete(key string) {
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+func (s *MemorySource) Watch(ctx context.Context, onChange func()) error {
+	changes := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	s.watchers[changes] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.watchers, changes)
+			s.mu.Unlock()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+				onChange()
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *MemorySource) notify() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for changes := range s.watchers {
+		// Changes made while a watcher is busy are coalesced
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// mergeRemote merges the values of the source into the configuration
+func (l *layers) mergeRemote(source Source) error {
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteTimeout)
+	defer cancel()
+
+	values, err := source.Values(ctx)
+	if err != nil {
+		return fmt.Errorf("configfx: failed to load remote configuration: %w", err)
+	}
+
+	settings := make(map[string]any, len(values))
+
+	// Keys are merged in order, so a key mapped from several names is set
+	// deterministically
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		key := strings.ToLower(strings.ReplaceAll(strings.Trim(name, "/"), "/", "."))
+		if key == "" {
+			continue
+		}
+
+		if err := setNested(settings, key, values[name]); err != nil {
+			return fmt.Errorf("configfx: remote key %s: %w", name, err)
+		}
+
+		l.sources[key] = SourceRemotePrefix + name
+	}
+
+	if err := l.v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("configfx: failed to merge remote configuration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package configfx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+)
+
+var errBackendDown = errors.New("backend down")
+
+type failingSource struct{}
+
+func (failingSource) Values(context.Context) (map[string]string, error) {
+	return nil, errBackendDown
+}
+
+func TestLoadRemoteSource(t *testing.T) {
+	dir := writeConfig(t, `
+name: from-file
+replicas: 1
+database:
+  host: localhost
+  port: 5432
+`)
+
+	t.Setenv("MY_APP_REPLICAS", "5")
+
+	source := configfx.NewMemorySource(map[string]string{
+		"database/host": "db.eu-west-1.internal",
+		"/Replicas":     "3",
+		"tags":          "a,b",
+	})
+
+	var dump bytes.Buffer
+
+	cfg, err := configfx.Load[testConfig]("my-app",
+		configfx.WithPaths(dir),
+		configfx.WithRemoteSource(source),
+		configfx.WithRemoteSource(configfx.NewMemorySource(map[string]string{"name": "from-remote"})),
+		configfx.WithSourceDump(&dump),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "from-remote", cfg.Name)
+	require.Equal(t, "db.eu-west-1.internal", cfg.Database.Host)
+	require.Equal(t, uint16(5432), cfg.Database.Port)
+	require.Equal(t, []string{"a", "b"}, cfg.Tags)
+	require.Equal(t, 5, cfg.Replicas, "environment variables take precedence")
+
+	require.Contains(t, dump.String(), "database.host = db.eu-west-1.internal  # remote database/host\n")
+
+	_, err = configfx.Load[testConfig]("my-app",
+		configfx.WithPaths(dir),
+		configfx.WithRemoteSource(failingSource{}),
+	)
+	require.ErrorIs(t, err, errBackendDown)
+}
+
+func TestLoadRemoteSourceConflictingKeys(t *testing.T) {
+	dir := writeConfig(t, "name: from-file\n")
+
+	for _, values := range []map[string]string{
+		{"database": "db.internal", "database/host": "localhost"},
+		{"database/host": "localhost", "database/host/primary": "db.internal"},
+	} {
+		_, err := configfx.Load[testConfig]("my-app",
+			configfx.WithPaths(dir),
+			configfx.WithRemoteSource(configfx.NewMemorySource(values)),
+		)
+		require.ErrorIs(t, err, configfx.ErrConflictingKey)
+	}
+
+	// Names mapped to the same key are merged in order
+	for range 10 {
+		cfg, err := configfx.Load[testConfig]("my-app",
+			configfx.WithPaths(dir),
+			configfx.WithRemoteSource(configfx.NewMemorySource(map[string]string{
+				"/name": "first",
+				"Name":  "second",
+				"name/": "third",
+			})),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "third", cfg.Name)
+	}
+}
+
+func TestWatchModuleRemoteSource(t *testing.T) {
+	dir := writeConfig(t, "mode: fast\n")
+	source := configfx.NewMemorySource(map[string]string{"workers": "2"})
+
+	var watcher *configfx.Watcher[validatedConfig]
+
+	app := fxtest.New(t,
+		configfx.WatchModule[validatedConfig]("watch-app",
+			configfx.WithPaths(dir),
+			configfx.WithRemoteSource(source),
+		),
+		fx.Populate(&watcher),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	require.Equal(t, validatedConfig{Mode: "fast", Workers: 2}, watcher.Current())
+
+	changes := make(chan change, 10)
+	watcher.Subscribe(func(old, new validatedConfig) {
+		changes <- change{old: old, new: new}
+	})
+
+	source.Set("workers", "6")
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "fast", Workers: 6}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	source.Delete("workers")
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "fast", Workers: 4}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+// blockingSource blocks the load following a call to block until release
+type blockingSource struct {
+	onChange func()
+	blocked  chan struct{}
+	release  chan struct{}
+	block    atomic.Bool
+}
+
+func (s *blockingSource) Values(context.Context) (map[string]string, error) {
+	if s.block.CompareAndSwap(true, false) {
+		close(s.blocked)
+		<-s.release
+	}
+
+	return nil, nil
+}
+
+func (s *blockingSource) Watch(_ context.Context, onChange func()) error {
+	s.onChange = onChange
+	return nil
+}
+
+func TestWatchModuleSerializesReloads(t *testing.T) {
+	dir := writeConfig(t, "mode: fast\n")
+	memory := configfx.NewMemorySource(map[string]string{"workers": "1"})
+	slow := &blockingSource{blocked: make(chan struct{}), release: make(chan struct{})}
+
+	var watcher *configfx.Watcher[validatedConfig]
+
+	app := fxtest.New(t,
+		configfx.WatchModule[validatedConfig]("watch-app",
+			configfx.WithPaths(dir),
+			configfx.WithRemoteSource(memory),
+			configfx.WithRemoteSource(slow),
+		),
+		fx.Populate(&watcher),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	// A reload of the slow source reads workers: 1, then blocks
+	slow.block.Store(true)
+
+	stale := make(chan struct{})
+
+	go func() {
+		defer close(stale)
+		slow.onChange()
+	}()
+
+	<-slow.blocked
+
+	// A newer change must not be overwritten by the blocked reload
+	memory.Set("workers", "2")
+	time.Sleep(50 * time.Millisecond)
+	close(slow.release)
+	<-stale
+
+	require.Eventually(t, func() bool {
+		return watcher.Current().Workers == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,492 @@
+package configfx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SchemaDraft is the JSON Schema dialect of the schemas generated by JSONSchema
+	SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	// durationPattern matches the strings parsed by time.ParseDuration, every
+	// unit following a number with at least one digit
+	durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+)$`
+)
+
+var (
+	// ErrUnknownKey is wrapped by the error of every key of a config file
+	// that is not a field of the configuration
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrInvalidValue is wrapped by the error of every value of a config file
+	// that does not match the type of its field
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// Schema is the JSON Schema of a configuration, see JSONSchema
+type Schema struct {
+	// AdditionalProperties is false for structs, the Schema of the values
+	// for maps
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// JSONSchema returns the JSON Schema of the config files of T. Properties are
+// named after the keys of the fields (see BindEnv), the "default" tags are
+// their defaults and the fields tagged `required:"true"` without a default
+// are required. A time.Duration is a string like "5s", a time.Time a
+// date-time string and the other encoding.TextUnmarshaler types strings.
+// Keys that are not fields of a struct are not allowed.
+func JSONSchema[T any]() *Schema {
+	s := typeSchema(reflect.TypeFor[T](), make(map[reflect.Type]struct{}))
+	s.Schema = SchemaDraft
+
+	return s
+}
+
+// ValidateFile validates the config file against the JSON Schema of T, see
+// JSONSchema. Every key that is unknown, missing or of the wrong type is
+// reported, the errors combined with multierr. The values of dotenv files are
+// named like environment variables, see Load.
+func ValidateFile[T any](appName, file string, options ...Option) error {
+	opts := newOptions(appName, options)
+	opts.keys = envKeys(reflect.TypeFor[T](), opts)
+
+	settings, _, err := readConfigFile(file, opts)
+	if err != nil {
+		return err
+	}
+
+	return JSONSchema[T]().validate("", settings, configTypeOf(file) == "dotenv")
+}
+
+// SampleYAML writes a YAML config file of T with every key set to its default,
+// or to the zero value of its field. Required keys without a default are
+// commented as such.
+func SampleYAML[T any](w io.Writer) error {
+	node, err := sampleNode(reflect.TypeFor[T](), "", make(map[reflect.Type]struct{}))
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// typeSchema returns the schema of the type, visiting holding the struct
+// types on the path to t. A struct nested in itself is any value.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]struct{}) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		return &Schema{Type: "string", Pattern: durationPattern}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0.0
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: typeSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if _, ok := visiting[t]; ok {
+			return &Schema{}
+		}
+
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+		addProperties(s, t, visiting)
+
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addProperties adds the fields of the struct type t to the object schema,
+// flattening squashed structs
+func addProperties(s *Schema, t reflect.Type, visiting map[reflect.Type]struct{}) {
+	if _, ok := visiting[t]; ok {
+		return
+	}
+
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, squash, skip := fieldName(sf)
+		if skip {
+			continue
+		}
+
+		if squash {
+			ft := sf.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			addProperties(s, ft, visiting)
+
+			continue
+		}
+
+		p := typeSchema(sf.Type, visiting)
+
+		def, hasDefault := sf.Tag.Lookup("default")
+		if hasDefault {
+			p.Default = defaultValue(sf.Type, def)
+		}
+
+		if required, _ := strconv.ParseBool(sf.Tag.Get("required")); required && !hasDefault {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = p
+	}
+}
+
+// defaultValue returns the value of the default tag as the JSON value of the
+// type, the tag itself when it is not a number or a boolean
+func defaultValue(t reflect.Type, tag string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return tag
+	}
+
+	var (
+		value any
+		err   error
+	)
+
+	switch t.Kind() {
+	case reflect.Bool:
+		value, err = strconv.ParseBool(tag)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(tag, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err = strconv.ParseUint(tag, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(tag, 64)
+	default:
+		return tag
+	}
+
+	if err != nil {
+		return tag
+	}
+
+	return value
+}
+
+// validate validates the value of the key path. Strings are accepted for
+// numbers and booleans parsing as such when lenient, e.g. in dotenv files.
+func (s *Schema) validate(path string, value any, lenient bool) error {
+	if value == nil {
+		return nil
+	}
+
+	invalid := func() error {
+		return fmt.Errorf("%s: %w: expected %s, got %s", displayPath(path), ErrInvalidValue, s.Type, jsonType(value))
+	}
+
+	switch s.Type {
+	case "object":
+		m, ok := value.(map[string]any)
+		if !ok {
+			return invalid()
+		}
+
+		return s.validateObject(path, m, lenient)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			if _, isString := value.(string); lenient && isString {
+				return nil
+			}
+
+			return invalid()
+		}
+
+		var err error
+
+		for i, item := range items {
+			err = multierr.Append(err, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, lenient))
+		}
+
+		return err
+	case "string":
+		switch v := value.(type) {
+		case string:
+			if matched, _ := regexp.MatchString(s.Pattern, v); s.Pattern != "" && !matched {
+				return fmt.Errorf("%s: %w: %q does not match %s", displayPath(path), ErrInvalidValue, v, s.Pattern)
+			}
+
+			return nil
+		case time.Time:
+			if s.Format == "date-time" {
+				return nil
+			}
+		}
+
+		return invalid()
+	case "integer", "number":
+		n, ok := number(value, lenient)
+		if !ok || (s.Type == "integer" && n != math.Trunc(n)) {
+			return invalid()
+		}
+
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s: %w: %v is less than %v", displayPath(path), ErrInvalidValue, value, *s.Minimum)
+		}
+
+		return nil
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return nil
+		case string:
+			if _, err := strconv.ParseBool(v); lenient && err == nil {
+				return nil
+			}
+		}
+
+		return invalid()
+	default:
+		return nil
+	}
+}
+
+func (s *Schema) validateObject(path string, m map[string]any, lenient bool) error {
+	var err error
+
+	for _, key := range s.Required {
+		if _, ok := m[key]; !ok {
+			err = multierr.Append(err, fmt.Errorf("%s: %w", joinPath(path, key), ErrMissingKey))
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		p, ok := s.Properties[key]
+		if !ok {
+			switch additional := s.AdditionalProperties.(type) {
+			case *Schema:
+				p = additional
+			case bool:
+				if !additional {
+					err = multierr.Append(err, fmt.Errorf("%s: %w", joinPath(path, key), ErrUnknownKey))
+					continue
+				}
+			}
+		}
+
+		if p != nil {
+			err = multierr.Append(err, p.validate(joinPath(path, key), m[key], lenient))
+		}
+	}
+
+	return err
+}
+
+// sampleNode returns the YAML node of the default, or zero, value of the type,
+// visiting holding the struct types on the path to t. A struct nested in
+// itself is null.
+func sampleNode(t reflect.Type, def string, visiting map[reflect.Type]struct{}) (*yaml.Node, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if isNested(t) {
+		if _, ok := visiting[t]; ok {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if err := addSampleFields(node, t, visiting); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+
+	switch {
+	case def != "":
+		if err := node.Encode(defaultValue(t, def)); err != nil {
+			return nil, err
+		}
+	case t == durationType:
+		node.SetString(time.Duration(0).String())
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		node.Kind, node.Style = yaml.SequenceNode, yaml.FlowStyle
+	case t.Kind() == reflect.Map:
+		node.Kind, node.Style = yaml.MappingNode, yaml.FlowStyle
+	default:
+		if err := node.Encode(reflect.Zero(t).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// addSampleFields adds the keys of the fields of the struct type t to the
+// mapping node, in the order of the fields
+func addSampleFields(node *yaml.Node, t reflect.Type, visiting map[reflect.Type]struct{}) error {
+	if _, ok := visiting[t]; ok {
+		return nil
+	}
+
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, squash, skip := fieldName(sf)
+		if skip {
+			continue
+		}
+
+		if squash {
+			ft := sf.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if err := addSampleFields(node, ft, visiting); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		def, hasDefault := sf.Tag.Lookup("default")
+
+		value, err := sampleNode(sf.Type, def, visiting)
+		if err != nil {
+			return err
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+		if required, _ := strconv.ParseBool(sf.Tag.Get("required")); required && !hasDefault {
+			key.LineComment = "required"
+		}
+
+		node.Content = append(node.Content, key, value)
+	}
+
+	return nil
+}
+
+// number returns the value of a YAML, TOML or JSON number, or of a string
+// parsing as a number when lenient
+func number(value any, lenient bool) (float64, bool) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		if !lenient {
+			return 0, false
+		}
+
+		n, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// jsonType returns the JSON type of a decoded value
+func jsonType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+
+	if _, ok := number(value, false); ok {
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+
+	return path
+}
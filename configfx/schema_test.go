@@ -0,0 +1,187 @@
+package configfx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+)
+
+type sampleConfig struct {
+	Started      time.Time         `mapstructure:"started"`
+	Labels       map[string]string `mapstructure:"labels"`
+	CommonConfig `mapstructure:",squash"`
+	Name         string        `mapstructure:"name"    required:"true"`
+	Tags         []string      `mapstructure:"tags"`
+	Ignored      string        `mapstructure:"-"`
+	Database     testDatabase  `mapstructure:"database"`
+	Timeout      time.Duration `mapstructure:"timeout" default:"5s"`
+	Workers      int           `mapstructure:"workers" default:"4"`
+	Debug        bool          `mapstructure:"debug"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(configfx.JSONSchema[appConfig]())
+	require.NoError(t, err)
+
+	var schema struct {
+		Properties struct {
+			Database struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Type       string                    `json:"type"`
+				Required   []string                  `json:"required"`
+				Additional bool                      `json:"additionalProperties"`
+			} `json:"database"`
+		} `json:"properties"`
+		Schema string `json:"$schema"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	database := schema.Properties.Database
+	require.Equal(t, configfx.SchemaDraft, schema.Schema)
+	require.Equal(t, "object", database.Type)
+	require.False(t, database.Additional)
+	require.ElementsMatch(t, []string{
+		"application_name",
+		"dbname",
+		"password",
+		"username",
+		"max_idle_connections",
+		"max_open_connections",
+		"max_connection_lifetime",
+		"max_connection_idle_time",
+	}, database.Required)
+
+	require.Equal(t, map[string]any{"type": "integer", "minimum": 0.0, "default": 5432.0}, database.Properties["port"])
+	require.Equal(t, "5s", database.Properties["connection_timeout"]["default"])
+	require.Equal(t, "string", database.Properties["connection_timeout"]["type"])
+	require.NotEmpty(t, database.Properties["connection_timeout"]["pattern"])
+}
+
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+)$`
+
+func TestValidateFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+name: app
+environment: production
+timeout: 1m30s
+workers: 8
+tags: [a, b]
+labels:
+  team: platform
+database:
+  host: localhost
+  port: 5432
+  connection_timeout: .5s
+`), 0o600))
+	require.NoError(t, configfx.ValidateFile[sampleConfig]("my-app", valid))
+
+	invalid := filepath.Join(dir, "invalid.toml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+timeout = "5 seconds"
+workers = 1.5
+debug = "yes"
+tags = ["a", 1]
+unknown = true
+
+[database]
+port = -1
+hots = "localhost"
+connection_timeout = "ms"
+`), 0o600))
+
+	err := configfx.ValidateFile[sampleConfig]("my-app", invalid)
+	require.ErrorIs(t, err, configfx.ErrMissingKey)
+	require.ErrorIs(t, err, configfx.ErrUnknownKey)
+	require.ErrorIs(t, err, configfx.ErrInvalidValue)
+
+	var messages []string
+	for _, e := range multierr.Errors(err) {
+		messages = append(messages, e.Error())
+	}
+
+	require.Equal(t, []string{
+		"name: required key is missing",
+		`database.connection_timeout: invalid value: "ms" does not match ` + durationPattern,
+		"database.hots: unknown key",
+		"database.port: invalid value: -1 is less than 0",
+		"debug: invalid value: expected boolean, got string",
+		"tags[1]: invalid value: expected string, got number",
+		`timeout: invalid value: "5 seconds" does not match ` + durationPattern,
+		"unknown: unknown key",
+		"workers: invalid value: expected integer, got number",
+	}, messages)
+
+	dotenv := filepath.Join(dir, "config.env")
+	require.NoError(t, os.WriteFile(dotenv, []byte("MY_APP_NAME=app\nDATABASE_PORT=5432\nDEBUG=true\nWORKERS=many\n"), 0o600))
+
+	err = configfx.ValidateFile[sampleConfig]("my-app", dotenv)
+	require.EqualError(t, err, "workers: invalid value: expected integer, got string")
+
+	err = configfx.ValidateFile[sampleConfig]("my-app", filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+	require.False(t, errors.Is(err, configfx.ErrInvalidValue))
+}
+
+func TestSampleYAML(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, configfx.SampleYAML[sampleConfig](&out))
+
+	require.Equal(t, `started: 0001-01-01T00:00:00Z
+labels: {}
+environment: ""
+name: "" # required
+tags: []
+database:
+  host: ""
+  connection_timeout: 0s
+  port: 0
+timeout: 5s
+workers: 4
+debug: false
+`, out.String())
+
+	// The sample is a valid configuration
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, out.Bytes(), 0o600))
+	require.NoError(t, configfx.ValidateFile[sampleConfig]("my-app", file))
+
+	cfg, err := configfx.Load[sampleConfig]("my-app", configfx.WithConfigFile(file), configfx.WithoutEnv())
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, cfg.Timeout)
+	require.Equal(t, 4, cfg.Workers)
+}
+
+func TestSchemaSelfReferentialConfig(t *testing.T) {
+	t.Parallel()
+
+	schema := configfx.JSONSchema[nodeConfig]()
+	require.Equal(t, &configfx.Schema{}, schema.Properties["next"])
+	require.Equal(t, "root", schema.Properties["name"].Default)
+
+	var out bytes.Buffer
+	require.NoError(t, configfx.SampleYAML[nodeConfig](&out))
+	require.Equal(t, "next: null\nname: root\n", out.String())
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("name: a\nnext:\n  name: b\n"), 0o600))
+	require.NoError(t, configfx.ValidateFile[nodeConfig]("my-app", file))
+}
@@ -0,0 +1,155 @@
+package configfx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/multierr"
+)
+
+// Redacted replaces the value of keys set from a secret reference in the
+// source dump
+const Redacted = "<redacted>"
+
+var (
+	// ErrUnknownSecretScheme is returned for a secret reference without a
+	// resolver for its scheme
+	ErrUnknownSecretScheme = errors.New("unknown secret scheme")
+	// ErrSecretNotFound is returned by resolvers for a missing secret
+	ErrSecretNotFound = errors.New("secret not found")
+
+	// secretReference matches ${scheme:reference}, e.g. ${file:/run/secrets/db_password}
+	secretReference = regexp.MustCompile(`\$\{([a-z][a-z0-9_-]*):([^}]*)\}`)
+)
+
+type (
+	// SecretResolver resolves the reference of a secret, the part after the
+	// scheme of ${scheme:reference}, to its value
+	SecretResolver interface {
+		Resolve(reference string) (string, error)
+	}
+
+	// SecretResolverFunc adapts a function to a SecretResolver
+	SecretResolverFunc func(reference string) (string, error)
+
+	// SecretReader reads the key-value pairs of the secret at path, e.g. from
+	// the KV engine of Vault, see VaultResolver
+	SecretReader interface {
+		ReadSecret(path string) (map[string]any, error)
+	}
+)
+
+func (f SecretResolverFunc) Resolve(reference string) (string, error) {
+	return f(reference)
+}
+
+// FileResolver resolves ${file:/path} to the content of the file without the
+// trailing newline, e.g. a Docker or Kubernetes secret
+func FileResolver() SecretResolver {
+	return SecretResolverFunc(func(path string) (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), nil
+	})
+}
+
+// EnvResolver resolves ${env:NAME} to the value of the environment variable
+func EnvResolver() SecretResolver {
+	return SecretResolverFunc(func(name string) (string, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, name)
+		}
+
+		return value, nil
+	})
+}
+
+// VaultResolver resolves ${vault:path#key} to the key of the secret read at
+// path. It is registered with WithSecretResolver("vault", VaultResolver(r)),
+// r wrapping the client of the secret store.
+func VaultResolver(r SecretReader) SecretResolver {
+	return SecretResolverFunc(func(reference string) (string, error) {
+		path, key, ok := strings.Cut(reference, "#")
+		if !ok || path == "" || key == "" {
+			return "", fmt.Errorf("invalid reference %q, expected path#key", reference)
+		}
+
+		secret, err := r.ReadSecret(path)
+		if err != nil {
+			return "", err
+		}
+
+		value, ok := secret[key]
+		if !ok {
+			return "", fmt.Errorf("%w: %s has no key %s", ErrSecretNotFound, path, key)
+		}
+
+		return fmt.Sprint(value), nil
+	})
+}
+
+// ResolveSecrets replaces the secret references of every string value of v by
+// the value of the resolver of their scheme, e.g. ${env:DB_PASS}. It returns
+// the keys set from a secret and an error for every value failing to
+// resolve, combined with multierr.
+func ResolveSecrets(v *viper.Viper, resolvers map[string]SecretResolver) ([]string, error) {
+	var (
+		keys []string
+		errs error
+	)
+
+	for _, key := range v.AllKeys() {
+		value, ok := v.Get(key).(string)
+		if !ok || !secretReference.MatchString(value) {
+			continue
+		}
+
+		resolved, err := resolveReferences(value, resolvers)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+
+		v.Set(key, resolved)
+		keys = append(keys, key)
+	}
+
+	return keys, errs
+}
+
+func resolveReferences(value string, resolvers map[string]SecretResolver) (string, error) {
+	var err error
+
+	resolved := secretReference.ReplaceAllStringFunc(value, func(reference string) string {
+		if err != nil {
+			return reference
+		}
+
+		match := secretReference.FindStringSubmatch(reference)
+
+		resolver, ok := resolvers[match[1]]
+		if !ok {
+			err = fmt.Errorf("%w: %s", ErrUnknownSecretScheme, reference)
+			return reference
+		}
+
+		var secret string
+
+		if secret, err = resolver.Resolve(match[2]); err != nil {
+			err = fmt.Errorf("failed to resolve %s: %w", reference, err)
+			return reference
+		}
+
+		return secret
+	})
+
+	return resolved, err
+}
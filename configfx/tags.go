@@ -0,0 +1,56 @@
+package configfx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/invopop/validation"
+	"github.com/spf13/viper"
+	"go.uber.org/multierr"
+)
+
+// ErrMissingKey is wrapped by the error of every required key missing from
+// the configuration, see multierr.Errors
+var ErrMissingKey = errors.New("required key is missing")
+
+// SetDefaults sets the value of the "default" tag of every field of T as the
+// viper default of its key. Values are decoded like the values of a config
+// file, e.g. "5s" for a time.Duration.
+func SetDefaults[T any](v *viper.Viper) {
+	for _, f := range fields(reflect.TypeFor[T]()) {
+		if value, ok := f.StructField.Tag.Lookup("default"); ok {
+			v.SetDefault(f.Path, value)
+		}
+	}
+}
+
+// CheckRequired returns an error for every field of T tagged
+// `required:"true"` whose key is not set in any source of v, defaults
+// included. The errors wrap ErrMissingKey and are combined with multierr.
+func CheckRequired[T any](v *viper.Viper) error {
+	var err error
+
+	for _, f := range fields(reflect.TypeFor[T]()) {
+		if required, _ := strconv.ParseBool(f.StructField.Tag.Get("required")); required && !v.IsSet(f.Path) {
+			err = multierr.Append(err, fmt.Errorf("%s: %w", f.Path, ErrMissingKey))
+		}
+	}
+
+	return err
+}
+
+// validate runs the Validate method of the configuration when T, or *T,
+// implements validation.Validatable
+func validate[T any](c *T) error {
+	if v, ok := any(c).(validation.Validatable); ok {
+		return v.Validate()
+	}
+
+	if v, ok := any(*c).(validation.Validatable); ok {
+		return v.Validate()
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package configfx_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/databasesfx"
+)
+
+type (
+	appConfig struct {
+		Database databasesfx.PostgresConfig `mapstructure:"database"`
+	}
+
+	validatedConfig struct {
+		Mode    string `mapstructure:"mode"    default:"fast"`
+		Workers int    `mapstructure:"workers" default:"4"`
+	}
+)
+
+var errInvalidMode = errors.New("unknown mode")
+
+func (c validatedConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Mode, validation.In("fast", "safe").Error(errInvalidMode.Error())),
+	)
+}
+
+func TestDefaults(t *testing.T) {
+	dir := writeConfig(t, `
+database:
+  application_name: app
+  dbname: app
+  username: app
+  password: secret
+  max_idle_connections: 5
+  max_open_connections: 10
+  max_connection_lifetime: 1h
+  max_connection_idle_time: 10m
+`)
+
+	cfg, err := configfx.New[appConfig]("app", dir)
+	require.NoError(t, err)
+
+	require.Equal(t, uint16(5432), cfg.Database.Port)
+	require.Equal(t, 5*time.Second, cfg.Database.ConnectionTimeout)
+	require.Equal(t, "localhost", cfg.Database.Host)
+	require.Equal(t, "public", cfg.Database.Schema)
+	require.Equal(t, "UTC", cfg.Database.Timezone)
+	require.Equal(t, "disable", cfg.Database.SslMode)
+	require.Equal(t, time.Hour, cfg.Database.MaxConnectionLifetime)
+}
+
+func TestRequired(t *testing.T) {
+	dir := writeConfig(t, `
+database:
+  application_name: app
+  dbname: app
+  max_idle_connections: 5
+  max_open_connections: 10
+  max_connection_lifetime: 1h
+`)
+
+	_, err := configfx.New[appConfig]("app", dir)
+	require.ErrorIs(t, err, configfx.ErrMissingKey)
+	require.Len(t, multierr.Errors(errors.Unwrap(err)), 3)
+	require.EqualError(t, err, "configfx: "+
+		"database.password: required key is missing; "+
+		"database.username: required key is missing; "+
+		"database.max_connection_idle_time: required key is missing")
+
+	// Environment variables satisfy required keys
+	t.Setenv("APP_DATABASE_PASSWORD", "secret")
+	t.Setenv("APP_DATABASE_USERNAME", "app")
+	t.Setenv("APP_DATABASE_MAX_CONNECTION_IDLE_TIME", "1m")
+
+	cfg, err := configfx.New[appConfig]("app", dir)
+	require.NoError(t, err)
+	require.Equal(t, "secret", cfg.Database.Password)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	v := viper.New()
+
+	cfg, err := configfx.NewWithViper[validatedConfig](v)
+	require.NoError(t, err)
+	require.Equal(t, validatedConfig{Mode: "fast", Workers: 4}, cfg)
+
+	v.Set("mode", "reckless")
+
+	_, err = configfx.NewWithViper[validatedConfig](v)
+	require.ErrorContains(t, err, "configfx: invalid configuration: Mode: "+errInvalidMode.Error())
+}
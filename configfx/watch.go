@@ -0,0 +1,268 @@
+package configfx
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+)
+
+// Watcher holds the latest valid configuration of watched config files
+type Watcher[T any] struct {
+	current     T
+	load        func() (T, error)
+	files       *fsnotify.Watcher
+	cancel      context.CancelFunc
+	subscribers []func(old, new T)
+	mu          sync.RWMutex
+	// reloading serializes reloads of the file and remote watchers, so a
+	// slow load never replaces a newer configuration and subscribers are
+	// notified in order
+	reloading sync.Mutex
+	stopped   bool
+}
+
+// WatchModule loads the configuration like Load and provides a *Watcher[T]
+// reloading it whenever the config file, its config.d fragments, the
+// environment overlay or a WatchableSource change, together with the initial
+// T. It replaces Module for configurations that change at runtime.
+func WatchModule[T any](appName string, options ...Option) fx.Option {
+	return fx.Module("config-watch",
+		fx.Provide(func(lc fx.Lifecycle) (*Watcher[T], error) {
+			opts := newOptions(appName, options)
+
+			load := func() (*layers, T, error) {
+				l, err := loadLayers[T](opts)
+				if err != nil {
+					var c T
+					return nil, c, err
+				}
+
+				c, err := NewWithViper[T](l.v)
+
+				return l, c, err
+			}
+
+			l, c, err := load()
+			if err != nil {
+				return nil, err
+			}
+
+			w := &Watcher[T]{
+				current: c,
+				load: func() (T, error) {
+					_, c, err := load()
+					return c, err
+				},
+			}
+
+			if err := w.watch(l); err != nil {
+				return nil, err
+			}
+
+			if err := w.watchRemote(opts.sources); err != nil {
+				_ = w.Stop(context.Background())
+				return nil, err
+			}
+
+			lc.Append(fx.StopHook(w.Stop))
+
+			return w, nil
+		}),
+		fx.Provide(func(w *Watcher[T]) T {
+			return w.Current()
+		}),
+	)
+}
+
+// NewWatcher unmarshals the configuration of v like NewWithViper and watches
+// the config file of v. On every change the configuration is unmarshalled and
+// validated again; a configuration that fails is logged and the last valid one
+// is kept.
+func NewWatcher[T any](v *viper.Viper) (*Watcher[T], error) {
+	c, err := NewWithViper[T](v)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher[T]{
+		current: c,
+		load: func() (T, error) {
+			return NewWithViper[T](v)
+		},
+	}
+
+	if v.ConfigFileUsed() != "" {
+		v.OnConfigChange(func(fsnotify.Event) {
+			w.reload()
+		})
+		v.WatchConfig()
+	}
+
+	return w, nil
+}
+
+// Current returns the latest valid configuration
+func (w *Watcher[T]) Current() T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// Subscribe calls fn with the previous and the new configuration every time a
+// changed configuration is loaded
+func (w *Watcher[T]) Subscribe(fn func(old, new T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Stop stops watching the config files and notifying subscribers
+func (w *Watcher[T]) Stop(context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+
+	if w.cancel != nil {
+		w.cancel()
+	}
+
+	if w.files != nil {
+		return w.files.Close()
+	}
+
+	return nil
+}
+
+// watch reloads the configuration on changes of the directory of the config
+// file to files named like it, e.g. config.production.yaml, on changes of the
+// fragments directory and when the real path of a config file changes, e.g.
+// when a Kubernetes ConfigMap volume swaps its ..data symlink
+func (w *Watcher[T]) watch(l *layers) error {
+	if len(l.files) == 0 {
+		return nil
+	}
+
+	files, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	base := l.files[0]
+	dir := filepath.Dir(base)
+	stem := strings.TrimSuffix(filepath.Base(base), filepath.Ext(base)) + "."
+
+	if err := files.Add(dir); err != nil {
+		_ = files.Close()
+		return err
+	}
+
+	// The fragments directory is optional
+	_ = files.Add(l.fragments)
+
+	w.files = files
+
+	watched := append(slices.Clone(l.files), l.fragments)
+	resolved := realPaths(watched)
+
+	go func() {
+		for event := range files.Events {
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) {
+				continue
+			}
+
+			name := filepath.Clean(event.Name)
+			current := realPaths(watched)
+			moved := !slices.Equal(resolved, current)
+			resolved = current
+
+			if moved || filepath.Dir(name) == filepath.Clean(l.fragments) ||
+				(filepath.Dir(name) == filepath.Clean(dir) && strings.HasPrefix(filepath.Base(name), stem)) {
+				w.reload()
+			}
+		}
+	}()
+
+	go func() {
+		for err := range files.Errors {
+			log.Error().Err(err).Msg("Failed to watch configuration files")
+		}
+	}()
+
+	return nil
+}
+
+// realPaths resolves the symlinks of the paths, keeping the paths that do
+// not exist as is
+func realPaths(paths []string) []string {
+	result := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+
+		result = append(result, path)
+	}
+
+	return result
+}
+
+// watchRemote reloads the configuration on changes of the sources
+// implementing WatchableSource
+func (w *Watcher[T]) watchRemote(sources []Source) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	for _, source := range sources {
+		if watchable, ok := source.(WatchableSource); ok {
+			if err := watchable.Watch(ctx, w.reload); err != nil {
+				return fmt.Errorf("configfx: failed to watch remote configuration: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher[T]) reload() {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	c, err := w.load()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to reload configuration, keeping the previous one")
+
+		return
+	}
+
+	w.mu.Lock()
+
+	old := w.current
+	if w.stopped || reflect.DeepEqual(old, c) {
+		w.mu.Unlock()
+		return
+	}
+
+	w.current = c
+	subscribers := w.subscribers
+
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, c)
+	}
+}
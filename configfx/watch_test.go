@@ -0,0 +1,155 @@
+package configfx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/configfx"
+)
+
+type change struct {
+	old, new validatedConfig
+}
+
+func TestWatchModule(t *testing.T) {
+	dir := writeConfig(t, "mode: fast\nworkers: 2\n")
+
+	var (
+		watcher *configfx.Watcher[validatedConfig]
+		initial validatedConfig
+	)
+
+	app := fxtest.New(t,
+		configfx.WatchModule[validatedConfig]("watch-app", configfx.WithPaths(dir)),
+		fx.Populate(&watcher, &initial),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	require.Equal(t, validatedConfig{Mode: "fast", Workers: 2}, initial)
+
+	changes := make(chan change, 10)
+	watcher.Subscribe(func(old, new validatedConfig) {
+		changes <- change{old: old, new: new}
+	})
+
+	// Replace the file atomically, a truncated file is a valid empty configuration
+	write := func(content string) {
+		tmp := filepath.Join(dir, "config.yaml.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte(content), 0o600))
+		require.NoError(t, os.Rename(tmp, filepath.Join(dir, "config.yaml")))
+	}
+
+	write("mode: safe\nworkers: 8\n")
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "fast", Workers: 2}, c.old)
+		require.Equal(t, validatedConfig{Mode: "safe", Workers: 8}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	require.Equal(t, validatedConfig{Mode: "safe", Workers: 8}, watcher.Current())
+
+	// An invalid configuration keeps the last valid one
+	write("mode: reckless\nworkers: 16\n")
+
+	select {
+	case c := <-changes:
+		t.Fatalf("invalid configuration was published: %+v", c.new)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	require.Equal(t, validatedConfig{Mode: "safe", Workers: 8}, watcher.Current())
+
+	write("mode: fast\nworkers: 1\n")
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "safe", Workers: 8}, c.old)
+		require.Equal(t, validatedConfig{Mode: "fast", Workers: 1}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+func TestWatchModuleFragments(t *testing.T) {
+	dir := writeConfig(t, "mode: fast\nworkers: 2\n")
+	writeFile(t, filepath.Join(dir, "config.d", "10-workers.yaml"), "workers: 3\n")
+
+	var watcher *configfx.Watcher[validatedConfig]
+
+	app := fxtest.New(t,
+		configfx.WatchModule[validatedConfig]("watch-app", configfx.WithPaths(dir)),
+		fx.Populate(&watcher),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	require.Equal(t, validatedConfig{Mode: "fast", Workers: 3}, watcher.Current())
+
+	changes := make(chan change, 10)
+	watcher.Subscribe(func(old, new validatedConfig) {
+		changes <- change{old: old, new: new}
+	})
+
+	tmp := filepath.Join(dir, "20-mode.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("mode: safe\n"), 0o600))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, "config.d", "20-mode.yaml")))
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "safe", Workers: 3}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+func TestWatchModuleSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	// The layout of a Kubernetes ConfigMap volume: config.yaml links to
+	// ..data/config.yaml and ..data links to the current version
+	writeVersion := func(version, content string) {
+		writeFile(t, filepath.Join(dir, version, "config.yaml"), content)
+	}
+
+	writeVersion("..2026_01_01", "mode: fast\nworkers: 2\n")
+	require.NoError(t, os.Symlink("..2026_01_01", filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), filepath.Join(dir, "config.yaml")))
+
+	var watcher *configfx.Watcher[validatedConfig]
+
+	app := fxtest.New(t,
+		configfx.WatchModule[validatedConfig]("watch-app", configfx.WithPaths(dir)),
+		fx.Populate(&watcher),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	require.Equal(t, validatedConfig{Mode: "fast", Workers: 2}, watcher.Current())
+
+	changes := make(chan change, 10)
+	watcher.Subscribe(func(old, new validatedConfig) {
+		changes <- change{old: old, new: new}
+	})
+
+	// Swap ..data atomically like the kubelet
+	writeVersion("..2026_01_02", "mode: safe\nworkers: 8\n")
+	require.NoError(t, os.Symlink("..2026_01_02", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+
+	select {
+	case c := <-changes:
+		require.Equal(t, validatedConfig{Mode: "safe", Workers: 8}, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
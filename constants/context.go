@@ -5,4 +5,8 @@ type ContextKey string
 const (
 	CancelFuncContextKey         ContextKey = "uberfxutils:cancel"
 	CancelWillBeCalledContextKey ContextKey = "uberfxutils:cancelFnWillBeCalled"
+	URLBuilderContextKey         ContextKey = "uberfxutils:urlBuilder"
+	ClientCertificateContextKey  ContextKey = "uberfxutils:clientCertificate"
+	RequestIDContextKey          ContextKey = "uberfxutils:requestId"
+	TraceParentContextKey        ContextKey = "uberfxutils:traceParent"
 )
@@ -0,0 +1,143 @@
+// Package correlation carries the request ID and W3C traceparent of a request
+// through contexts, so they can be propagated across HTTP, logs and AMQP.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/constants"
+)
+
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+
+	LogFieldRequestID = "request_id"
+	LogFieldTraceID   = "trace_id"
+
+	maxRequestIDLength = 128
+	traceParentLength  = 55
+)
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, constants.RequestIDContextKey, id)
+}
+
+// RequestID returns the request ID of the context, or an empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(constants.RequestIDContextKey).(string)
+
+	return id
+}
+
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, constants.TraceParentContextKey, traceParent)
+}
+
+// TraceParent returns the traceparent of the context, or an empty string
+func TraceParent(ctx context.Context) string {
+	traceParent, _ := ctx.Value(constants.TraceParentContextKey).(string)
+
+	return traceParent
+}
+
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+// ValidRequestID reports whether an incoming request ID can be used as is.
+// IDs must be printable ASCII without spaces and at most 128 characters long.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewTraceParent starts a new sampled trace
+func NewTraceParent() string {
+	return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+}
+
+// ValidTraceParent reports whether the value is a version 00 W3C traceparent
+// with non-zero trace and parent IDs
+func ValidTraceParent(traceParent string) bool {
+	if len(traceParent) != traceParentLength {
+		return false
+	}
+
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return false
+	}
+
+	for _, part := range parts[1:] {
+		if _, err := hex.DecodeString(part); err != nil || strings.ToLower(part) != part {
+			return false
+		}
+	}
+
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+// TraceID returns the trace ID of a valid traceparent, or an empty string
+func TraceID(traceParent string) string {
+	if !ValidTraceParent(traceParent) {
+		return ""
+	}
+
+	return traceParent[3:35]
+}
+
+// Logger adds the request ID and trace ID of the context to the logger
+func Logger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	l := logger.With()
+
+	if id := RequestID(ctx); id != "" {
+		l = l.Str(LogFieldRequestID, id)
+	}
+
+	if traceID := TraceID(TraceParent(ctx)); traceID != "" {
+		l = l.Str(LogFieldTraceID, traceID)
+	}
+
+	return l.Logger()
+}
+
+// Restore stores the request ID and traceparent in the context, generating
+// them when they are missing or invalid, and attaches a logger with both to
+// the context, available with zerolog.Ctx
+func Restore(ctx context.Context, requestID, traceParent string, logger zerolog.Logger) context.Context {
+	if !ValidRequestID(requestID) {
+		requestID = NewRequestID()
+	}
+
+	if !ValidTraceParent(traceParent) {
+		traceParent = NewTraceParent()
+	}
+
+	ctx = WithTraceParent(WithRequestID(ctx, requestID), traceParent)
+	l := Logger(ctx, logger)
+
+	return l.WithContext(ctx)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package correlation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestValidTraceParent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{value: traceParent, valid: true},
+		{value: correlation.NewTraceParent(), valid: true},
+		{value: "", valid: false},
+		{value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", valid: false},
+		{value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", valid: false},
+		{value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", valid: false},
+		{value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", valid: false},
+		{value: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", valid: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.valid, correlation.ValidTraceParent(test.value), test.value)
+	}
+
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", correlation.TraceID(traceParent))
+}
+
+func TestValidRequestID(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, correlation.ValidRequestID("abc-123"))
+	require.True(t, correlation.ValidRequestID(correlation.NewRequestID()))
+	require.False(t, correlation.ValidRequestID(""))
+	require.False(t, correlation.ValidRequestID("has space"))
+	require.False(t, correlation.ValidRequestID("new\nline"))
+	require.False(t, correlation.ValidRequestID(string(make([]byte, 129))))
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := fibertest.NewLogger(t, zerolog.InfoLevel)
+
+	ctx := correlation.Restore(context.Background(), "request-1", traceParent, logger)
+
+	require.Equal(t, "request-1", correlation.RequestID(ctx))
+	require.Equal(t, traceParent, correlation.TraceParent(ctx))
+
+	zerolog.Ctx(ctx).Info().Msg("handled")
+
+	entry := logs.RequireEntry(zerolog.InfoLevel, "handled")
+	require.Equal(t, "request-1", entry[correlation.LogFieldRequestID])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry[correlation.LogFieldTraceID])
+
+	// Invalid values are replaced
+	ctx = correlation.Restore(context.Background(), "bad id", "bad", logger)
+	require.True(t, correlation.ValidRequestID(correlation.RequestID(ctx)))
+	require.NotEqual(t, "bad id", correlation.RequestID(ctx))
+	require.True(t, correlation.ValidTraceParent(correlation.TraceParent(ctx)))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
@@ -60,12 +61,24 @@ func NewPostgresMigrations(fs fs.FS, cfg PostgresConfig, migrations, migrationsT
 	return migrate.NewWithInstance("iofs", sourceDriver, "pgx5", db)
 }
 
+// PostgresModule provides a *pgxpool.Pool closed on shutdown. When a
+// trace.TracerProvider is provided, e.g. by tracingfx.Module, every query is
+// traced with QueryTracer.
 func PostgresModule(cfg PostgresConfig) fx.Option {
-	return fx.Module("Databases-Postgres", fx.Provide(
-		func(lc fx.Lifecycle) (*pgxpool.Pool, error) {
+	return fx.Module("Databases-Postgres", fx.Provide(fx.Annotate(
+		func(lc fx.Lifecycle, tp trace.TracerProvider) (*pgxpool.Pool, error) {
+			poolConfig, err := pgxpool.ParseConfig(cfg.ConnectionString())
+			if err != nil {
+				return nil, err
+			}
+
+			if tp != nil {
+				poolConfig.ConnConfig.Tracer = NewQueryTracer(tp, cfg.DBName)
+			}
+
 			ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectionTimeout)
 			defer cancel()
-			conn, err := pgxpool.New(ctx, cfg.ConnectionString())
+			conn, err := pgxpool.NewWithConfig(ctx, poolConfig)
 			if err != nil {
 				return nil, err
 			}
@@ -76,8 +89,9 @@ func PostgresModule(cfg PostgresConfig) fx.Option {
 			}))
 
 			return conn, nil
-		}),
-	)
+		},
+		fx.ParamTags(``, `optional:"true"`),
+	)))
 }
 
 func PostgresMigrationsModule(mig fs.FS, cfg PostgresConfig, migrations string) fx.Option {
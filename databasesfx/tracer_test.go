@@ -0,0 +1,44 @@
+package databasesfx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/databasesfx"
+)
+
+func TestQueryTracer(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := databasesfx.NewQueryTracer(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), "app")
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "  select * from users where id = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "INSERT INTO users VALUES ($1)"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("duplicate key")})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "SELECT", spans[0].Name)
+	require.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	require.Contains(t, spans[0].Attributes, semconv.DBSystemPostgreSQL)
+	require.Contains(t, spans[0].Attributes, semconv.DBNamespace("app"))
+	require.Contains(t, spans[0].Attributes, semconv.DBQueryText("  select * from users where id = $1"))
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	require.Equal(t, "INSERT", spans[1].Name)
+	require.Equal(t, codes.Error, spans[1].Status.Code)
+	require.Equal(t, "duplicate key", spans[1].Status.Description)
+}
@@ -10,7 +10,7 @@ import (
 
 func Context() gofiber.Handler {
 	return func(ctx *gofiber.Ctx) error {
-		c, cancel := context.WithCancel(context.Background())
+		c, cancel := context.WithCancel(ctx.UserContext())
 
 		ctx.Locals(constants.CancelFuncContextKey, cancel)
 		ctx.SetUserContext(c)
@@ -1,6 +1,7 @@
 package fiber_test
 
 import (
+	"context"
 	"testing"
 
 	gofiber "github.com/gofiber/fiber/v2"
@@ -27,3 +28,27 @@ func TestContextMiddleware(t *testing.T) {
 
 	assert.NotNil(ctx.UserValue(constants.CancelFuncContextKey))
 }
+
+func TestContextMiddlewareKeepsUserContext(t *testing.T) {
+	t.Parallel()
+
+	type key struct{}
+
+	assert := require.New(t)
+	app := gofiber.New()
+	app.Use(func(ctx *gofiber.Ctx) error {
+		ctx.SetUserContext(context.WithValue(ctx.UserContext(), key{}, "value"))
+		return ctx.Next()
+	})
+	app.Use(fiber.Context())
+	app.Get("/", func(ctx *gofiber.Ctx) error {
+		assert.Equal("value", ctx.UserContext().Value(key{}))
+		return ctx.SendStatus(gofiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	h(ctx)
+
+	assert.Equal(gofiber.StatusOK, ctx.Response.StatusCode())
+}
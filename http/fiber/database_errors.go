@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StatusClientClosedRequest is reported when the client cancels the request
+const StatusClientClosedRequest = 499
+
+// DatabaseErrorMapper maps errors of a database driver to a problem type and
+// field errors, reporting whether the error was mapped, e.g. pgxerrors.Map
+type DatabaseErrorMapper func(err error) (ProblemType, []FieldError, bool)
+
+// WithDatabaseErrors maps database and driver errors to statuses: missing rows
+// (sql.ErrNoRows, which pgx.ErrNoRows wraps) and documents to 404, Mongo
+// duplicate keys to 409, deadlines and network timeouts to 504 and canceled
+// requests to 499. The mappers are tried first, e.g. pgxerrors.Map for
+// Postgres constraint violations, so the error handler does not import the
+// drivers. Mappings of the ErrorRegistry take precedence.
+func WithDatabaseErrors(mappers ...DatabaseErrorMapper) ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.databaseErrors = true
+		opts.databaseMappers = append(opts.databaseMappers, mappers...)
+	}
+}
+
+func databaseProblem(err error, mappers []DatabaseErrorMapper) (ProblemType, []FieldError, bool) {
+	for _, mapper := range mappers {
+		if problemType, fields, ok := mapper(err); ok {
+			return problemType, fields, true
+		}
+	}
+
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, mongo.ErrNoDocuments):
+		return ProblemType{
+			Status: gofiber.StatusNotFound,
+			Detail: "The requested resource was not found",
+		}, nil, true
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ProblemType{
+			Status: gofiber.StatusGatewayTimeout,
+			Detail: "The request timed out",
+		}, nil, true
+	case errors.Is(err, context.Canceled):
+		return ProblemType{
+			Status: StatusClientClosedRequest,
+			Title:  "Client Closed Request",
+			Detail: "The request was canceled",
+		}, nil, true
+	case mongo.IsDuplicateKeyError(err):
+		return ProblemType{
+			Status: gofiber.StatusConflict,
+			Detail: "The resource already exists",
+		}, nil, true
+	default:
+		return ProblemType{}, nil, false
+	}
+}
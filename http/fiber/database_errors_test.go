@@ -0,0 +1,91 @@
+package fiber_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return false }
+
+func TestDatabaseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err    error
+		name   string
+		status int
+	}{
+		{name: "pgx no rows", err: fmt.Errorf("get user: %w", pgx.ErrNoRows), status: http.StatusNotFound},
+		{name: "mongo no documents", err: mongo.ErrNoDocuments, status: http.StatusNotFound},
+		{name: "sql no rows", err: sql.ErrNoRows, status: http.StatusNotFound},
+		{name: "mongo duplicate key", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, status: http.StatusConflict},
+		{name: "postgres error without mapper", err: &pgconn.PgError{Code: "23505"}, status: http.StatusInternalServerError},
+		{name: "network timeout", err: &net.OpError{Op: "read", Err: timeoutError{}}, status: http.StatusGatewayTimeout},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), status: http.StatusGatewayTimeout},
+		{name: "canceled", err: context.Canceled, status: fiber.StatusClientClosedRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := gofiber.New(gofiber.Config{
+				ErrorHandler: fiber.NewErrorHandler(
+					fiber.WithErrorLogger(zerolog.Nop()),
+					fiber.WithProblemDetails(),
+					fiber.WithDatabaseErrors(),
+				),
+			})
+			app.Get("/", func(*gofiber.Ctx) error {
+				return test.err
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, test.status, resp.StatusCode)
+
+			var problem fiber.ProblemDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			require.Equal(t, test.status, problem.Status)
+			require.NotEmpty(t, problem.Title)
+			require.Empty(t, problem.Errors)
+		})
+	}
+}
+
+func TestDatabaseErrorsDisabled(t *testing.T) {
+	t.Parallel()
+
+	app := gofiber.New(gofiber.Config{
+		ErrorHandler: fiber.NewErrorHandler(fiber.WithErrorLogger(zerolog.Nop())),
+	})
+	app.Get("/", func(*gofiber.Ctx) error {
+		return pgx.ErrNoRows
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -2,62 +2,194 @@ package fiber
 
 import (
 	"errors"
+	"html/template"
 
 	gofiber "github.com/gofiber/fiber/v2"
 	"github.com/invopop/validation"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var ErrDefaultHandler = errors.New("default handler")
 
-type ErrorResponse struct {
-	Message any `json:"message,omitempty"`
+type (
+	ErrorResponse struct {
+		Message any `json:"message,omitempty"`
+	}
+
+	// ErrorHandlerOption configures the error handler created by NewErrorHandler
+	ErrorHandlerOption func(*errorHandlerOptions)
+
+	errorHandlerOptions struct {
+		logger          *zerolog.Logger
+		handler         gofiber.ErrorHandler
+		registry        *ErrorRegistry
+		template        *template.Template
+		databaseMappers []DatabaseErrorMapper
+		problemDetails  bool
+		negotiate       bool
+		databaseErrors  bool
+	}
+
+	// renderedError is the resolved error, rendered either as problem details
+	// or as the legacy body
+	renderedError struct {
+		legacy  any
+		problem ProblemDetails
+	}
+)
+
+// WithErrorLogger sets the logger for unexpected errors. By default the
+// logger of the request context (zerolog.Ctx) is used, falling back to the
+// global logger at the time of the request.
+func WithErrorLogger(logger zerolog.Logger) ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.logger = &logger
+	}
+}
+
+// WithCustomErrorHandler runs the handler first, falling through to the
+// default handling when it returns ErrDefaultHandler
+func WithCustomErrorHandler(handler gofiber.ErrorHandler) ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.handler = handler
+	}
+}
+
+// WithErrorRegistry maps domain errors to status codes and problem types
+func WithErrorRegistry(registry *ErrorRegistry) ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.registry = registry
+	}
+}
+
+// WithProblemDetails renders errors as RFC 9457 application/problem+json
+// instead of {"message": ...}
+func WithProblemDetails() ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.problemDetails = true
+	}
 }
 
 func ErrorHandler() gofiber.ErrorHandler {
-	return ErrorHandlerWithCustomHandler(log.Logger, nil)
+	return NewErrorHandler()
 }
 
 func ErrorHandlerWithCustomHandler(logger zerolog.Logger, handler gofiber.ErrorHandler) gofiber.ErrorHandler {
+	return NewErrorHandler(WithErrorLogger(logger), WithCustomErrorHandler(handler))
+}
+
+func NewErrorHandler(options ...ErrorHandlerOption) gofiber.ErrorHandler {
+	var opts errorHandlerOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
 	return func(c *gofiber.Ctx, err error) error {
-		if handler != nil {
-			if errH := handler(c, err); !errors.Is(errH, ErrDefaultHandler) {
+		if opts.handler != nil {
+			if errH := opts.handler(c, err); !errors.Is(errH, ErrDefaultHandler) {
 				return errH
 			}
 		}
 
-		c.Set(gofiber.HeaderContentType, gofiber.MIMEApplicationJSONCharsetUTF8)
+		rendered := opts.resolve(c, err)
 
-		if errors.Is(err, primitive.ErrInvalidHex) {
-			return c.Status(gofiber.StatusBadRequest).JSON(ErrorResponse{
-				Message: "Invalid JSON Payload, check your input",
-			})
+		c.Status(rendered.problem.Status)
+
+		return opts.render(c, rendered)
+	}
+}
+
+// NextHandled calls the next handler and passes its error to the error
+// handler of the app, so middlewares running before the response is written,
+// e.g. to trace, observe or log the request, see its final status. A failing
+// error handler results in a 500. The handled error is returned for
+// reporting, e.g. on a span; the middleware must not return it again.
+func NextHandled(c *gofiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+		_ = c.SendStatus(gofiber.StatusInternalServerError)
+	}
+
+	return err
+}
+
+func (opts *errorHandlerOptions) resolve(c *gofiber.Ctx, err error) renderedError {
+	if problemType, ok := opts.registry.Lookup(err); ok {
+		if problemType.Detail == "" {
+			problemType.Detail = err.Error()
 		}
 
-		{
-			var fiberErr *gofiber.Error
-			if errors.As(err, &fiberErr) {
-				return c.Status(fiberErr.Code).JSON(ErrorResponse{
-					Message: fiberErr.Message,
-				})
-			}
+		problem := NewProblemDetails(c, problemType)
+
+		return renderedError{
+			problem: problem,
+			legacy:  ErrorResponse{Message: problem.Detail},
 		}
+	}
 
-		{
-			var validationErr validation.Errors
-			if errors.As(err, &validationErr) {
-				return c.Status(gofiber.StatusUnprocessableEntity).JSON(validationErr)
+	if opts.databaseErrors {
+		if problemType, fields, ok := databaseProblem(err, opts.databaseMappers); ok {
+			problem := NewProblemDetails(c, problemType)
+			problem.Errors = fields
+
+			return renderedError{
+				problem: problem,
+				legacy:  ErrorResponse{Message: problem.Detail},
 			}
 		}
+	}
+
+	if errors.Is(err, primitive.ErrInvalidHex) {
+		return newRenderedError(c, gofiber.StatusBadRequest, "Invalid JSON Payload, check your input")
+	}
+
+	{
+		var fiberErr *gofiber.Error
+		if errors.As(err, &fiberErr) {
+			return newRenderedError(c, fiberErr.Code, fiberErr.Message)
+		}
+	}
+
+	{
+		var validationErr validation.Errors
+		if errors.As(err, &validationErr) {
+			problem := NewProblemDetails(c, ProblemType{
+				Status: gofiber.StatusUnprocessableEntity,
+				Detail: "The request contains invalid fields",
+			})
+			problem.Errors = FieldErrors(validationErr)
+
+			return renderedError{problem: problem, legacy: validationErr}
+		}
+	}
 
-		logger.Error().Err(err).
-			Str("path", c.Route().Path).
-			Msg("Failed to process request")
+	opts.loggerFor(c).Error().Err(err).
+		Str("path", c.Route().Path).
+		Msg("Failed to process request")
+
+	return renderedError{
+		problem: NewProblemDetails(c, ProblemType{Status: gofiber.StatusInternalServerError}),
+		legacy:  ErrorResponse{Message: "An error has occurred!"},
+	}
+}
+
+func (opts *errorHandlerOptions) loggerFor(c *gofiber.Ctx) *zerolog.Logger {
+	if opts.logger != nil {
+		return opts.logger
+	}
+
+	return RequestLogger(c)
+}
 
-		return c.Status(gofiber.StatusInternalServerError).JSON(ErrorResponse{
-			Message: "An error has occurred!",
-		})
+func newRenderedError(c *gofiber.Ctx, status int, message string) renderedError {
+	return renderedError{
+		problem: NewProblemDetails(c, ProblemType{Status: status, Detail: message}),
+		legacy:  ErrorResponse{Message: message},
 	}
 }
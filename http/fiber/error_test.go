@@ -1,96 +1,228 @@
 package fiber_test
 
-// func setupErrorHandlerApp(t *testing.T) (*fiber.App, *zltest.Tester) {
-// 	// v, translations := testing_utils.GetValidator()
-
-// 	logger, loggerTest := nanotesting.NewAppTestLogger(t, zerolog.InfoLevel)
-
-// 	app := fiber.New(fiber.Config{
-// 		ErrorHandler: handlers.Error(logger, nil),
-// 	})
-
-// 	return app, v, loggerTest
-// }
-
-// func TestErrorHandler_ReturnFiberError(t *testing.T) {
-// 	t.Parallel()
-// 	assert := require.New(t)
-
-// 	app, _, _ := setupErrorHandlerApp(t)
-
-// 	app.Get("/", func(ctx *fiber.Ctx) error {
-// 		return fiber.ErrBadGateway
-// 	})
-
-// 	m := struct {
-// 		Message string `json:"message"`
-// 	}{}
-// 	res := testing_utils.Get(app, "/")
-
-// 	assert.EqualValues(fiber.StatusBadGateway, res.StatusCode)
-// 	assert.EqualValues(fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
-// 	assert.Nil(json.NewDecoder(res.Body).Decode(&m))
-// 	assert.NotEmpty(m.Message)
-// }
-
-// func TestErrorHandler_InvalidPayloadError(t *testing.T) {
-// 	t.Parallel()
-// 	assert := require.New(t)
-
-// 	app, _, _ := setupErrorHandlerApp(t)
-// 	app.Get("/", func(ctx *fiber.Ctx) error {
-// 		return handlers.ErrInvalidPayload
-// 	})
-
-// 	res := testing_utils.Get(app, "/")
-
-// 	m := struct {
-// 		Message string `json:"message"`
-// 	}{}
-
-// 	assert.EqualValues(fiber.StatusBadRequest, res.StatusCode)
-// 	assert.EqualValues(fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
-// 	assert.Nil(json.NewDecoder(res.Body).Decode(&m))
-// 	assert.NotEmpty(m.Message)
-// 	assert.Equal(handlers.ErrInvalidPayload.Error(), m.Message)
-// }
-
-// func TestErrorHandler_ValidationError(t *testing.T) {
-// 	t.Parallel()
-// 	assert := require.New(t)
-// 	app, _, _ := setupErrorHandlerApp(t)
-// 	app.Get("/", func(ctx *fiber.Ctx) error {
-// 		return validator.ValidationErrors{}
-// 	})
-// 	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
-// 	assert.Nil(err)
-// 	assert.EqualValues(fiber.StatusUnprocessableEntity, res.StatusCode)
-// 	assert.EqualValues(fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
-// }
-
-// func TestErrorHandler_InvalidValidationError(t *testing.T) {
-// 	t.Parallel()
-// 	assert := require.New(t)
-
-// 	app, _, _ := setupErrorHandlerApp(t)
-// 	app.Get("/", func(ctx *fiber.Ctx) error {
-// 		return &validator.InvalidValidationError{}
-// 	})
-// 	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
-// 	assert.Nil(err)
-// 	assert.EqualValues(fiber.StatusUnprocessableEntity, res.StatusCode)
-// 	assert.EqualValues(fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
-// }
-
-// func TestErrorHandler_AnyError(t *testing.T) {
-// 	t.Parallel()
-// 	assert := require.New(t)
-// 	app, _, _ := setupErrorHandlerApp(t)
-// 	app.Get("/", func(ctx *fiber.Ctx) error {
-// 		return errors.New("any other error")
-// 	})
-// 	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
-// 	assert.Nil(err)
-// 	assert.EqualValues(fiber.StatusInternalServerError, res.StatusCode)
-// 	assert.EqualValues(fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
-// }
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+func setupErrorHandlerApp(t *testing.T, handler gofiber.ErrorHandler, routeErr error) (*gofiber.App, *fibertest.LogCapture) {
+	t.Helper()
+
+	logger, logs := fibertest.NewLogger(t, zerolog.InfoLevel)
+
+	app := gofiber.New(gofiber.Config{
+		ErrorHandler: fiber.ErrorHandlerWithCustomHandler(logger, handler),
+	})
+
+	app.Get("/", func(*gofiber.Ctx) error {
+		return routeErr
+	})
+
+	return app, logs
+}
+
+func TestErrorHandler_ReturnFiberError(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	app, logs := setupErrorHandlerApp(t, nil, gofiber.ErrBadGateway)
+
+	res := fibertest.Get(t, "/").Do(app)
+
+	assert.Equal(gofiber.StatusBadGateway, res.StatusCode)
+	assert.Equal(gofiber.MIMEApplicationJSONCharsetUTF8, res.ContentType())
+	assert.Equal(gofiber.ErrBadGateway.Message, fibertest.DecodeJSON[fiber.ErrorResponse](res).Message)
+	logs.RequireEmpty()
+}
+
+func TestErrorHandler_InvalidPayloadError(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	app, logs := setupErrorHandlerApp(t, nil, fmt.Errorf("parsing id: %w", primitive.ErrInvalidHex))
+
+	res := fibertest.Get(t, "/").Do(app)
+
+	assert.Equal(gofiber.StatusBadRequest, res.StatusCode)
+	assert.Equal(gofiber.MIMEApplicationJSONCharsetUTF8, res.ContentType())
+	assert.Equal("Invalid JSON Payload, check your input", fibertest.DecodeJSON[fiber.ErrorResponse](res).Message)
+	logs.RequireEmpty()
+}
+
+func TestErrorHandler_ValidationError(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	app, logs := setupErrorHandlerApp(t, nil, validation.Errors{
+		"name": validation.ErrRequired,
+	})
+
+	res := fibertest.Get(t, "/").Do(app)
+
+	assert.Equal(gofiber.StatusUnprocessableEntity, res.StatusCode)
+	assert.Equal(gofiber.MIMEApplicationJSONCharsetUTF8, res.ContentType())
+	assert.Equal(map[string]string{"name": "cannot be blank"}, fibertest.DecodeJSON[map[string]string](res))
+	logs.RequireEmpty()
+}
+
+func TestErrorHandler_AnyError(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	app, logs := setupErrorHandlerApp(t, nil, errors.New("any other error"))
+
+	res := fibertest.Get(t, "/").Do(app)
+
+	assert.Equal(gofiber.StatusInternalServerError, res.StatusCode)
+	assert.Equal(gofiber.MIMEApplicationJSONCharsetUTF8, res.ContentType())
+	assert.Equal("An error has occurred!", fibertest.DecodeJSON[fiber.ErrorResponse](res).Message)
+
+	entry := logs.RequireEntry(zerolog.ErrorLevel, "Failed to process request")
+	assert.Equal("any other error", entry[zerolog.ErrorFieldName])
+	assert.Equal("/", entry["path"])
+}
+
+func TestErrorHandler_CustomHandler(t *testing.T) {
+	t.Parallel()
+
+	errTeapot := errors.New("teapot")
+
+	handler := func(c *gofiber.Ctx, err error) error {
+		if errors.Is(err, errTeapot) {
+			return c.Status(gofiber.StatusTeapot).SendString("short and stout")
+		}
+
+		return fmt.Errorf("not handled: %w", fiber.ErrDefaultHandler)
+	}
+
+	t.Run("handled", func(t *testing.T) {
+		t.Parallel()
+		assert := require.New(t)
+
+		app, logs := setupErrorHandlerApp(t, handler, errTeapot)
+
+		res := fibertest.Get(t, "/").Do(app)
+
+		assert.Equal(gofiber.StatusTeapot, res.StatusCode)
+		assert.Equal("short and stout", res.String())
+		logs.RequireEmpty()
+	})
+
+	t.Run("falls through with ErrDefaultHandler", func(t *testing.T) {
+		t.Parallel()
+		assert := require.New(t)
+
+		app, _ := setupErrorHandlerApp(t, handler, gofiber.ErrNotFound)
+
+		res := fibertest.Get(t, "/").Do(app)
+
+		assert.Equal(gofiber.StatusNotFound, res.StatusCode)
+		assert.Equal(gofiber.MIMEApplicationJSONCharsetUTF8, res.ContentType())
+		assert.Equal(gofiber.ErrNotFound.Message, fibertest.DecodeJSON[fiber.ErrorResponse](res).Message)
+	})
+
+	t.Run("handler error is returned", func(t *testing.T) {
+		t.Parallel()
+		assert := require.New(t)
+
+		app, _ := setupErrorHandlerApp(t, func(*gofiber.Ctx, error) error {
+			return gofiber.ErrServiceUnavailable
+		}, errTeapot)
+
+		// fiber responds with a plain 500 when the error handler fails
+		res := fibertest.Get(t, "/").Do(app)
+
+		assert.Equal(gofiber.StatusInternalServerError, res.StatusCode)
+		assert.Equal(gofiber.ErrInternalServerError.Message, res.String())
+	})
+}
+
+func TestErrorHandler_FiberfxApp(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	cfg := fiber.DefaultFiberConfig
+	cfg.ErrorHandler = fiber.NewErrorHandler(fiber.WithErrorLogger(zerolog.Nop()), fiber.WithProblemDetails())
+
+	app := fibertest.NewApp(t, "errorsapp",
+		fiberfx.App("errorsapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Post("/users", func(c *gofiber.Ctx) error {
+				var body struct {
+					Name string `json:"name"`
+				}
+
+				if err := c.BodyParser(&body); err != nil {
+					return err
+				}
+
+				return validation.Errors{"name": validation.Validate(body.Name, validation.Required)}.Filter()
+			}),
+		}), fiberfx.WithFiberConfig(cfg)),
+	)
+
+	res := fibertest.Post(t, "/users").
+		Header(gofiber.HeaderXRequestID, "request-1").
+		JSON(map[string]string{"name": ""}).
+		Do(app)
+
+	assert.Equal(gofiber.StatusUnprocessableEntity, res.StatusCode)
+	assert.Equal(fiber.MIMEApplicationProblemJSON, res.ContentType())
+
+	problem := fibertest.DecodeJSON[fiber.ProblemDetails](res)
+	assert.Equal("request-1", problem.RequestID)
+	assert.Equal([]fiber.FieldError{{Field: "name", Message: "cannot be blank", Code: "validation_required"}}, problem.Errors)
+
+	res = fibertest.Post(t, "/users").JSON(map[string]string{"name": "John"}).Do(app)
+	assert.Equal(gofiber.StatusOK, res.StatusCode)
+}
+
+func TestNextHandled(t *testing.T) {
+	t.Parallel()
+
+	var status int
+
+	app := gofiber.New(gofiber.Config{
+		ErrorHandler: func(c *gofiber.Ctx, err error) error {
+			if err.Error() == "unhandled" {
+				return err
+			}
+
+			return c.SendStatus(gofiber.StatusTeapot)
+		},
+	})
+	app.Use(func(c *gofiber.Ctx) error {
+		require.Error(t, fiber.NextHandled(c))
+		status = c.Response().StatusCode()
+
+		return nil
+	})
+	app.Get("/handled", func(*gofiber.Ctx) error {
+		return errors.New("handled")
+	})
+	app.Get("/unhandled", func(*gofiber.Ctx) error {
+		return errors.New("unhandled")
+	})
+
+	for path, want := range map[string]int{
+		"/handled":   gofiber.StatusTeapot,
+		"/unhandled": gofiber.StatusInternalServerError,
+	} {
+		resp, err := app.Test(httptest.NewRequest(gofiber.MethodGet, path, nil))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, want, resp.StatusCode)
+		require.Equal(t, want, status)
+	}
+}
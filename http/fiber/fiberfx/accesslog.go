@@ -0,0 +1,139 @@
+package fiberfx
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+type (
+	// AccessLogOption configures the access log
+	AccessLogOption func(*accessLogOptions)
+
+	accessLogOptions struct {
+		exclude  []string
+		sampling uint32
+		level    zerolog.Level
+	}
+)
+
+// WithAccessLogLevel sets the level of successful requests, zerolog.InfoLevel by default.
+// Client errors are logged as warnings and server errors as errors.
+func WithAccessLogLevel(level zerolog.Level) AccessLogOption {
+	return func(opts *accessLogOptions) {
+		opts.level = level
+	}
+}
+
+// WithAccessLogSampling logs one in every n successful requests,
+// requests failing with a 4xx or 5xx status are always logged
+func WithAccessLogSampling(n uint32) AccessLogOption {
+	return func(opts *accessLogOptions) {
+		opts.sampling = n
+	}
+}
+
+// WithAccessLogExclude skips logging requests to the paths. Paths ending
+// with "*" match by prefix, e.g. "/health*".
+func WithAccessLogExclude(paths ...string) AccessLogOption {
+	return func(opts *accessLogOptions) {
+		opts.exclude = append(opts.exclude, paths...)
+	}
+}
+
+// WithAccessLog logs every request of the app with the zerolog.Logger from the
+// container (e.g. loggerfx.ZerologModule), falling back to the global logger.
+// See AccessLog.
+func WithAccessLog(options ...AccessLogOption) Option {
+	return func(opts *appOptions) {
+		opts.accessLog = append(opts.accessLog, options...)
+		opts.useAccessLog = true
+	}
+}
+
+// AccessLog logs the method, route, status, latency, response size, client IP
+// and request ID of every request. A child logger with the request ID and
+// trace ID is stored in the user context, retrievable with zerolog.Ctx. The
+// request ID is taken from fiber.RequestID when it runs first, otherwise it is
+// accepted or generated the same way.
+//
+// The final status is logged, see fiber.NextHandled. App registers it
+// before the recover middleware, so requests whose handler panics are logged
+// with status 500.
+func AccessLog(logger zerolog.Logger, options ...AccessLogOption) fiber.Handler {
+	opts := accessLogOptions{level: zerolog.InfoLevel}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	var counter atomic.Uint32
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		ctx := c.UserContext()
+
+		if correlation.RequestID(ctx) == "" {
+			traceParent := correlation.TraceParent(ctx)
+			if traceParent == "" {
+				traceParent = c.Get(correlation.HeaderTraceParent)
+			}
+
+			ctx = correlation.Restore(ctx, c.Get(correlation.HeaderRequestID), traceParent, logger)
+			c.Set(correlation.HeaderRequestID, correlation.RequestID(ctx))
+		}
+
+		requestLogger := correlation.Logger(ctx, logger)
+		c.SetUserContext(requestLogger.WithContext(ctx))
+
+		_ = corehttp.NextHandled(c)
+
+		status := c.Response().StatusCode()
+
+		if opts.excluded(c.Path()) {
+			return nil
+		}
+
+		level := opts.level
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = zerolog.ErrorLevel
+		case status >= fiber.StatusBadRequest:
+			level = zerolog.WarnLevel
+		case opts.sampling > 1 && counter.Add(1)%opts.sampling != 1:
+			return nil
+		}
+
+		requestLogger.WithLevel(level).
+			Str("method", c.Method()).
+			Str("route", c.Route().Path).
+			Int("status", status).
+			Dur("latency", time.Since(start)).
+			Int("bytes", len(c.Response().Body())).
+			Str("ip", c.IP()).
+			Msg("Request")
+
+		return nil
+	}
+}
+
+func (opts *accessLogOptions) excluded(path string) bool {
+	for _, exclude := range opts.exclude {
+		if prefix, ok := strings.CutSuffix(exclude, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == exclude {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package fiberfx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestAccessLog tests the access log with the logger from the container
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := fibertest.NewLogger(t, zerolog.DebugLevel)
+
+	app := fibertest.NewApp(t, "accesslogapp",
+		fx.Supply(logger),
+		fiberfx.App("accesslogapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/users/:id", func(c *fiber.Ctx) error {
+				zerolog.Ctx(c.UserContext()).Info().Msg("Loading user")
+				return c.SendString("user")
+			}),
+			fiberfx.Get("/fail", func(*fiber.Ctx) error {
+				return errors.New("failed")
+			}),
+			fiberfx.Get("/panic", func(*fiber.Ctx) error {
+				panic("failed")
+			}),
+			fiberfx.Get("/health/live", fiberfx.RouteTestHandler),
+		}), fiberfx.WithAccessLog(fiberfx.WithAccessLogExclude("/health*")), fiberfx.WithAfterCreate(func(app *fiber.App) {
+			app.Use(corehttp.RequestID(corehttp.WithRequestIDLogger(logger)))
+		})),
+	)
+
+	t.Run("success", func(t *testing.T) {
+		res := fibertest.Get(t, "/users/5").Header(correlation.HeaderRequestID, "request-1").Do(app)
+		require.Equal(t, fiber.StatusOK, res.StatusCode)
+		require.Equal(t, "request-1", res.Header.Get(correlation.HeaderRequestID))
+
+		inside := logs.RequireEntry(zerolog.InfoLevel, "Loading user")
+		require.Equal(t, "request-1", inside[correlation.LogFieldRequestID])
+
+		entry := logs.RequireEntry(zerolog.InfoLevel, "Request")
+		require.Equal(t, "request-1", entry[correlation.LogFieldRequestID])
+		require.NotEmpty(t, entry[correlation.LogFieldTraceID])
+		require.Equal(t, fiber.MethodGet, entry["method"])
+		require.Equal(t, "/users/:id", entry["route"])
+		require.InDelta(t, fiber.StatusOK, entry["status"], 0)
+		require.InDelta(t, len("user"), entry["bytes"], 0)
+		require.Equal(t, "0.0.0.0", entry["ip"])
+		require.Contains(t, entry, "latency")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		logs.Reset()
+
+		res := fibertest.Get(t, "/fail").Header(correlation.HeaderRequestID, "request-2").Do(app)
+		require.Equal(t, fiber.StatusInternalServerError, res.StatusCode)
+
+		// The error handler logs with the request logger
+		failed := logs.RequireEntry(zerolog.ErrorLevel, "Failed to process request")
+		require.Equal(t, "request-2", failed[correlation.LogFieldRequestID])
+
+		entry := logs.RequireEntry(zerolog.ErrorLevel, "Request")
+		require.InDelta(t, fiber.StatusInternalServerError, entry["status"], 0)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		logs.Reset()
+
+		res := fibertest.Get(t, "/panic").Header(correlation.HeaderRequestID, "request-3").Do(app)
+		require.Equal(t, fiber.StatusInternalServerError, res.StatusCode)
+
+		entry := logs.RequireEntry(zerolog.ErrorLevel, "Request")
+		require.Equal(t, "request-3", entry[correlation.LogFieldRequestID])
+		require.Equal(t, "/panic", entry["route"])
+		require.InDelta(t, fiber.StatusInternalServerError, entry["status"], 0)
+	})
+
+	t.Run("generated request ID", func(t *testing.T) {
+		logs.Reset()
+
+		res := fibertest.Get(t, "/users/5").Do(app)
+		require.Equal(t, fiber.StatusOK, res.StatusCode)
+
+		// fiber.RequestID runs after the access log and keeps its request ID
+		inside := logs.RequireEntry(zerolog.InfoLevel, "Loading user")
+		entry := logs.RequireEntry(zerolog.InfoLevel, "Request")
+		require.NotEmpty(t, entry[correlation.LogFieldRequestID])
+		require.Equal(t, entry[correlation.LogFieldRequestID], inside[correlation.LogFieldRequestID])
+		require.Equal(t, entry[correlation.LogFieldRequestID], res.Header.Get(correlation.HeaderRequestID))
+	})
+
+	t.Run("excluded", func(t *testing.T) {
+		logs.Reset()
+
+		res := fibertest.Get(t, "/health/live").Do(app)
+		require.Equal(t, fiber.StatusOK, res.StatusCode)
+		logs.RequireEmpty()
+	})
+}
+
+// TestAccessLogSampling tests that only sampled successful requests are logged
+func TestAccessLogSampling(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := fibertest.NewLogger(t, zerolog.DebugLevel)
+
+	app := fiber.New()
+	app.Use(fiberfx.AccessLog(logger, fiberfx.WithAccessLogSampling(3), fiberfx.WithAccessLogLevel(zerolog.DebugLevel)))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for range 6 {
+		fibertest.Get(t, "/").Do(app)
+	}
+
+	require.Len(t, logs.Entries(), 2)
+	logs.RequireEntry(zerolog.DebugLevel, "Request")
+
+	// Client errors are always logged
+	logs.Reset()
+
+	for range 2 {
+		fibertest.Get(t, "/missing").Do(app)
+	}
+
+	require.Len(t, logs.Entries(), 2)
+	logs.RequireEntry(zerolog.WarnLevel, "Request")
+}
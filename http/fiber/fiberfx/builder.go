@@ -0,0 +1,182 @@
+package fiberfx
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// RouteBuilder builds a RouteFx with any combination of plain middlewares,
+// middlewares with dependencies, router callbacks, a name and metadata
+//
+//	fiberfx.GET("/users/:id").
+//		Use(cors.New()).
+//		UseFx(NewAuthMiddleware).
+//		Name("user.show").
+//		Handler(ShowUser)
+type RouteBuilder struct {
+	metadata    map[string]any
+	name        string
+	spec        routeSpec
+	middlewares []middlewareEntry
+	callbacks   []func(fiber.Router)
+}
+
+// NewRoute starts building a route for the given method
+func NewRoute(method, path string) *RouteBuilder {
+	return &RouteBuilder{spec: routeSpec{methods: []string{method}, path: path}}
+}
+
+func GET(path string) *RouteBuilder {
+	return NewRoute(http.MethodGet, path)
+}
+
+func POST(path string) *RouteBuilder {
+	return NewRoute(http.MethodPost, path)
+}
+
+func PUT(path string) *RouteBuilder {
+	return NewRoute(http.MethodPut, path)
+}
+
+func PATCH(path string) *RouteBuilder {
+	return NewRoute(http.MethodPatch, path)
+}
+
+func DELETE(path string) *RouteBuilder {
+	return NewRoute(http.MethodDelete, path)
+}
+
+func HEAD(path string) *RouteBuilder {
+	return NewRoute(http.MethodHead, path)
+}
+
+func OPTIONS(path string) *RouteBuilder {
+	return NewRoute(http.MethodOptions, path)
+}
+
+func CONNECT(path string) *RouteBuilder {
+	return NewRoute(http.MethodConnect, path)
+}
+
+func TRACE(path string) *RouteBuilder {
+	return NewRoute(http.MethodTrace, path)
+}
+
+// ALL starts building a route matching every request method of the app
+func ALL(path string) *RouteBuilder {
+	return &RouteBuilder{spec: routeSpec{all: true, path: path}}
+}
+
+// MATCH starts building a route matching each of the given request methods.
+// The app fails with ErrInvalidMethod when methods is empty or contains a
+// method it does not accept. The name and callbacks apply to the route of
+// every method.
+func MATCH(methods []string, path string) *RouteBuilder {
+	return &RouteBuilder{spec: routeSpec{methods: methods, path: path}}
+}
+
+// Use adds plain middlewares, applied in the order they were added
+func (b *RouteBuilder) Use(middlewares ...fiber.Handler) *RouteBuilder {
+	for _, m := range middlewares {
+		b.middlewares = append(b.middlewares, middlewareEntry{handler: m})
+	}
+
+	return b
+}
+
+// UseFx adds middleware constructors whose dependencies are injected by uberfx
+func (b *RouteBuilder) UseFx(middlewareFuncs ...RouteMiddlewareFunc) *RouteBuilder {
+	for _, m := range middlewareFuncs {
+		b.middlewares = append(b.middlewares, middlewareEntry{ctor: m})
+	}
+
+	return b
+}
+
+// Name sets the name of the route
+func (b *RouteBuilder) Name(name string) *RouteBuilder {
+	b.name = name
+
+	return b
+}
+
+// Meta attaches metadata to the route, which is reported in the route table
+func (b *RouteBuilder) Meta(key string, value any) *RouteBuilder {
+	if b.metadata == nil {
+		b.metadata = make(map[string]any)
+	}
+
+	b.metadata[key] = value
+
+	return b
+}
+
+// Callback adds a callback that is called with the router after the route is registered
+func (b *RouteBuilder) Callback(cb func(fiber.Router)) *RouteBuilder {
+	if cb != nil {
+		b.callbacks = append(b.callbacks, cb)
+	}
+
+	return b
+}
+
+// Handler completes the route. The handler must be a fiber.Handler or a
+// func() fiber.Handler.
+func (b *RouteBuilder) Handler(handler any) RouteFx {
+	source := callerLocation()
+
+	// Copy the builder, so changing it afterwards does not affect this route
+	spec := b.spec
+	name := b.name
+	metadata := maps.Clone(b.metadata)
+	middlewares := slices.Clone(b.middlewares)
+	callback := combineCallbacks(slices.Clone(b.callbacks))
+
+	return func(appName, prefix string) fx.Option {
+		h := toFiberHandler(handler)
+		options, tags, merge := provideMiddlewares(appName, "route", middlewares)
+
+		// Create the route once all middleware handlers are resolved
+		options = append(options, fx.Provide(
+			fx.Annotate(
+				handlersFunc(len(tags), func(resolved []fiber.Handler) route {
+					return route{
+						Prefix:      prefix,
+						Methods:     spec.methods,
+						All:         spec.all,
+						Path:        spec.path,
+						Source:      source,
+						Name:        name,
+						Metadata:    metadata,
+						Handler:     h,
+						CallBack:    callback,
+						Middlewares: merge(resolved),
+					}
+				}),
+				fx.ParamTags(tags...),
+				fx.ResultTags(fiberHandlerRoutes(appName)),
+			),
+		))
+
+		return fx.Options(options...)
+	}
+}
+
+func combineCallbacks(callbacks []func(fiber.Router)) func(fiber.Router) {
+	switch len(callbacks) {
+	case 0:
+		return nil
+	case 1:
+		return callbacks[0]
+	default:
+		return func(router fiber.Router) {
+			for _, cb := range callbacks {
+				cb(router)
+			}
+		}
+	}
+}
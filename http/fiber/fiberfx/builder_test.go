@@ -0,0 +1,166 @@
+package fiberfx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestRouteBuilder tests combining every option of the route builder
+func TestRouteBuilder(t *testing.T) {
+	t.Parallel()
+
+	type TestDep struct {
+		Value string
+	}
+
+	var callbackCalls atomic.Int32
+
+	fiberApp := fibertest.NewApp(t, "builderapp",
+		fx.Supply(TestDep{Value: "fx"}),
+		fiberfx.App("builderapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Group("/users", fiberfx.WithGroupRoutes(
+				fiberfx.GET("/:id").
+					Use(tagMiddleware("plain-1")).
+					UseFx(func(dep TestDep) fiber.Handler { return tagMiddleware(dep.Value) }).
+					Use(tagMiddleware("plain-2")).
+					Name("user.show").
+					Meta("auth", "required").
+					Callback(func(_ fiber.Router) { callbackCalls.Add(1) }).
+					Handler(fiberfx.RouteTestHandler),
+			)),
+			fiberfx.MATCH([]string{http.MethodPut, http.MethodPatch}, "/items").Handler(fiberfx.RouteTestHandler),
+			fiberfx.ALL("/any").Handler(fiberfx.RouteTestHandler),
+		}), fiberfx.WithRouteTableEndpoint("/debug/routes")),
+	)
+
+	t.Run("middlewares in declaration order", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "plain-1, fx, plain-2", resp.Header.Get("X-Trace"))
+		require.Equal(t, int32(1), callbackCalls.Load())
+	})
+
+	t.Run("route is named in fiber", func(t *testing.T) {
+		t.Parallel()
+
+		route := fiberApp.GetRoute("user.show")
+		require.Equal(t, "/users/:id", route.Path)
+	})
+
+	t.Run("match and all", func(t *testing.T) {
+		t.Parallel()
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodPut, "/items", nil),
+			httptest.NewRequest(http.MethodPatch, "/items", nil),
+			httptest.NewRequest(http.MethodDelete, "/any", nil),
+		} {
+			resp, err := fiberApp.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("name and metadata in route table", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+		require.NoError(t, err)
+
+		var infos []fiberfx.RouteInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&infos))
+
+		var info fiberfx.RouteInfo
+		for _, i := range infos {
+			if i.Name == "user.show" {
+				info = i
+			}
+		}
+
+		require.Equal(t, "/users/:id", info.Path)
+		require.Equal(t, map[string]any{"auth": "required"}, info.Metadata)
+	})
+}
+
+// TestRouteBuilderIsCopied tests that changing a builder does not change routes created from it
+func TestRouteBuilderIsCopied(t *testing.T) {
+	t.Parallel()
+
+	builder := fiberfx.GET("/copied").Use(tagMiddleware("first"))
+	route := builder.Handler(fiberfx.RouteTestHandler)
+	builder.Use(tagMiddleware("second"))
+
+	fiberApp := fibertest.NewApp(t, "copiedapp", fiberfx.App("copiedapp", fiberfx.Routes([]fiberfx.RouteFx{route})))
+
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/copied", nil))
+	require.NoError(t, err)
+	require.Equal(t, "first", resp.Header.Get("X-Trace"))
+}
+
+// TestRouteBuilderMatch tests the callbacks and names of every method of
+// MATCH and the rejection of invalid methods
+func TestRouteBuilderMatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("callback and name for every method", func(t *testing.T) {
+		t.Parallel()
+
+		var methods []string
+
+		fiberApp := fibertest.NewApp(t, "matchapp",
+			fiberfx.App("matchapp", fiberfx.Routes([]fiberfx.RouteFx{
+				fiberfx.MATCH([]string{http.MethodPut, http.MethodPatch}, "/items").
+					Name("items.update").
+					Callback(func(router fiber.Router) {
+						// The route added last is the one of the current method
+						routes := router.(*fiber.App).GetRoutes(true)
+						methods = append(methods, routes[len(routes)-1].Method)
+					}).
+					Handler(fiberfx.RouteTestHandler),
+			})),
+		)
+
+		require.Equal(t, []string{http.MethodPut, http.MethodPatch}, methods)
+
+		for _, route := range fiberApp.GetRoutes(true) {
+			if route.Path == "/items" {
+				require.Equal(t, "items.update", route.Name)
+			}
+		}
+	})
+
+	for name, methods := range map[string][]string{
+		"no methods":     nil,
+		"unknown method": {http.MethodGet, "FETCH"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fx.New(
+				fx.NopLogger,
+				fiberfx.App("invalidmatchapp", fiberfx.Routes([]fiberfx.RouteFx{
+					fiberfx.MATCH(methods, "/items").
+						Callback(func(fiber.Router) {}).
+						Handler(fiberfx.RouteTestHandler),
+				})),
+				fx.Invoke(fx.Annotate(func(*fiber.App) {}, fx.ParamTags(fiberfx.GetFiberApp("invalidmatchapp")))),
+			)
+
+			require.ErrorIs(t, app.Err(), fiberfx.ErrInvalidMethod)
+			require.ErrorContains(t, app.Err(), "builder_test.go")
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package fiberfx
 
 import (
-	"context"
-	"time"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/samber/lo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
+	"go.uber.org/multierr"
 
+	"github.com/CodeLieutenant/uberfx-common/v3/constants"
 	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/httpmetrics"
 )
 
 type (
@@ -22,29 +26,20 @@ type (
 	routerCallbacks struct {
 		cbs []routerCallback
 	}
-)
 
-func RunApp(addr, appName string, shutdownTimeout time.Duration) fx.Option {
-	return fx.Invoke(fx.Annotate(func(app *fiber.App, lc fx.Lifecycle) {
-		lc.Append(fx.StartStopHook(
-			func() {
-				go func() {
-					if err := app.Listen(addr); err != nil {
-						panic(err)
-					}
-				}()
-			},
-			func(ctx context.Context) error {
-				newCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
-				defer cancel()
-				return app.ShutdownWithContext(newCtx)
-			},
-		))
-	}, fx.ParamTags(
-		GetFiberApp(appName),
-		`optional:"true"`,
-	)))
-}
+	appParams struct {
+		callbacks   *routerCallbacks
+		urls        *URLBuilder
+		readiness   *Readiness
+		logger      zerolog.Logger
+		tracer      trace.TracerProvider
+		metrics     *httpmetrics.Metrics
+		gatherer    prometheus.Gatherer
+		handlers    []route
+		groups      []routeGroup
+		middlewares []middlewareWithPrefix
+	}
+)
 
 func App(appName string, routes RoutesFx, options ...Option) fx.Option {
 	opts := appOptions{
@@ -57,67 +52,44 @@ func App(appName string, routes RoutesFx, options ...Option) fx.Option {
 
 	var appProvide fx.Option
 
-	// Define a struct to inject the middlewares
-	type middlewareIn struct {
-		fx.In
-
-		Handlers    []route                `group:"fiber-handlers"`
-		Callbacks   *routerCallbacks       `name:"fiber-router-callbacks"`
-		Middlewares []middlewareWithPrefix `group:"fiber-middlewares"`
-	}
-
 	if opts.useMiddlewares {
 		// If middleware injection is enabled, include middlewares in the app creation
 		appProvide = fx.Provide(fx.Annotate(
-			func(handlers []route, cbs *routerCallbacks, middlewares []middlewareWithPrefix) *fiber.App {
-				app := corehttp.CreateApplication(opts.afterCreate, opts.cfg)
-
-				// Apply middlewares first
-				applyMiddlewares(app, middlewares)
-
-				for _, r := range handlers {
-					var router fiber.Router
-
-					if r.Prefix != "" {
-						router = app.Group(r.Prefix)
-					} else {
-						router = app
-					}
-
-					cb, exists := cbs.Find(r.Prefix)
-
-					if exists {
-						cb.Callback(router)
-					}
-
-					// Create a handler chain with route-specific middleware
-					var handlers []fiber.Handler
-					if len(r.Middlewares) > 0 {
-						handlers = append(handlers, r.Middlewares...)
-					}
-					handlers = append(handlers, r.Handler)
-
-					// Add the route with middleware
-					var rt fiber.Router
-					if len(handlers) == 1 {
-						// No middleware, just add the handler directly
-						rt = router.Add(r.Method, r.Path, r.Handler)
-					} else {
-						// Apply middleware to this specific route
-						rt = router.Add(r.Method, r.Path, handlers...)
-					}
-
-					// Apply callback if provided
-					if r.CallBack != nil {
-						r.CallBack(rt)
-					}
-				}
-
-				return app
+			func(
+				handlers []route,
+				groups []routeGroup,
+				cbs *routerCallbacks,
+				urls *URLBuilder,
+				readiness *Readiness,
+				logger zerolog.Logger,
+				tracer trace.TracerProvider,
+				metrics *httpmetrics.Metrics,
+				gatherer prometheus.Gatherer,
+				middlewares []middlewareWithPrefix,
+			) (*fiber.App, error) {
+				return newApplication(appName, opts, appParams{
+					handlers:    handlers,
+					groups:      groups,
+					callbacks:   cbs,
+					urls:        urls,
+					readiness:   readiness,
+					logger:      logger,
+					tracer:      tracer,
+					metrics:     metrics,
+					gatherer:    gatherer,
+					middlewares: middlewares,
+				})
 			},
 			fx.ParamTags(
 				fiberHandlerRoutes(appName),
+				fiberGroups(appName),
 				routerCallbacksName(appName),
+				GetURLBuilder(appName),
+				GetReadiness(appName),
+				`optional:"true"`,
+				`optional:"true"`,
+				`optional:"true"`,
+				`optional:"true"`,
 				`group:"fiber-middlewares"`,
 			),
 			fx.ResultTags(GetFiberApp(appName)),
@@ -125,52 +97,39 @@ func App(appName string, routes RoutesFx, options ...Option) fx.Option {
 	} else {
 		// Backward compatibility: don't include middlewares
 		appProvide = fx.Provide(fx.Annotate(
-			func(handlers []route, cbs *routerCallbacks) *fiber.App {
-				app := corehttp.CreateApplication(opts.afterCreate, opts.cfg)
-
-				for _, r := range handlers {
-					var router fiber.Router
-
-					if r.Prefix != "" {
-						router = app.Group(r.Prefix)
-					} else {
-						router = app
-					}
-
-					cb, exists := cbs.Find(r.Prefix)
-
-					if exists {
-						cb.Callback(router)
-					}
-
-					// Create a handler chain with route-specific middleware
-					var handlers []fiber.Handler
-					if len(r.Middlewares) > 0 {
-						handlers = append(handlers, r.Middlewares...)
-					}
-					handlers = append(handlers, r.Handler)
-
-					// Add the route with middleware
-					var rt fiber.Router
-					if len(handlers) == 1 {
-						// No middleware, just add the handler directly
-						rt = router.Add(r.Method, r.Path, r.Handler)
-					} else {
-						// Apply middleware to this specific route
-						rt = router.Add(r.Method, r.Path, handlers...)
-					}
-
-					// Apply callback if provided
-					if r.CallBack != nil {
-						r.CallBack(rt)
-					}
-				}
-
-				return app
+			func(
+				handlers []route,
+				groups []routeGroup,
+				cbs *routerCallbacks,
+				urls *URLBuilder,
+				readiness *Readiness,
+				logger zerolog.Logger,
+				tracer trace.TracerProvider,
+				metrics *httpmetrics.Metrics,
+				gatherer prometheus.Gatherer,
+			) (*fiber.App, error) {
+				return newApplication(appName, opts, appParams{
+					handlers:  handlers,
+					groups:    groups,
+					callbacks: cbs,
+					urls:      urls,
+					readiness: readiness,
+					logger:    logger,
+					tracer:    tracer,
+					metrics:   metrics,
+					gatherer:  gatherer,
+				})
 			},
 			fx.ParamTags(
 				fiberHandlerRoutes(appName),
+				fiberGroups(appName),
 				routerCallbacksName(appName),
+				GetURLBuilder(appName),
+				GetReadiness(appName),
+				`optional:"true"`,
+				`optional:"true"`,
+				`optional:"true"`,
+				`optional:"true"`,
 			),
 			fx.ResultTags(GetFiberApp(appName)),
 		))
@@ -183,11 +142,138 @@ func App(appName string, routes RoutesFx, options ...Option) fx.Option {
 			},
 			fx.ResultTags(routerCallbacksName(appName)),
 		)),
+		fx.Supply(fx.Annotate(
+			&URLBuilder{},
+			fx.ResultTags(GetURLBuilder(appName)),
+		)),
+		fx.Supply(fx.Annotate(
+			&Readiness{},
+			fx.ResultTags(GetReadiness(appName)),
+		)),
 		routes(appName),
 		appProvide,
 	)
 }
 
+func newApplication(appName string, opts appOptions, params appParams) (*fiber.App, error) {
+	params.handlers = sortRoutes(params.handlers)
+	infos := routeInfos(params.handlers)
+
+	if err := multierr.Combine(
+		validateMethods(params.handlers, opts.cfg),
+		validateRoutes(infos, opts.cfg),
+		params.urls.set(infos),
+	); err != nil {
+		return nil, fmt.Errorf("fiber app %s: %w", appName, err)
+	}
+
+	// Tracing, metrics and the access log are registered before the recover
+	// middleware and the afterCreate middlewares, so their work and recovered
+	// panics are traced, observed and logged
+	var first []fiber.Handler
+
+	if params.tracer != nil {
+		first = append(first, Tracing(params.tracer))
+	}
+
+	if params.metrics != nil {
+		first = append(first, Metrics(params.metrics, appName))
+	}
+
+	if opts.useAccessLog {
+		first = append(first, AccessLog(loggerOrGlobal(params.logger), opts.accessLog...))
+	}
+
+	app := corehttp.CreateApplicationWith(first, opts.afterCreate, opts.cfg)
+
+	// Make the URLBuilder available to handlers
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(constants.URLBuilderContextKey, params.urls)
+		return c.Next()
+	})
+
+	// Apply middlewares first
+	applyMiddlewares(app, params.middlewares)
+
+	routers := newRouterTree(app)
+
+	// Group middlewares are registered parents first, before any route
+	for _, g := range sortGroups(params.groups) {
+		router := routers.Get(g.Prefix)
+
+		for _, m := range g.Middlewares {
+			router.Use(m)
+		}
+	}
+
+	// Every callback is called exactly once with the router of its prefix
+	for _, cb := range params.callbacks.Get() {
+		cb.Callback(routers.Get(cb.Prefix))
+	}
+
+	for _, r := range params.handlers {
+		router := routers.Get(r.Prefix)
+
+		// Create a handler chain with route-specific middleware
+		handlers := make([]fiber.Handler, 0, len(r.Middlewares)+1)
+		handlers = append(handlers, r.Middlewares...)
+		handlers = append(handlers, r.Handler)
+
+		// The callback is applied right after each route is added, e.g. once
+		// per method of Match, so it sees that route as the latest one
+		added := func(rt fiber.Router) {
+			if r.CallBack != nil {
+				r.CallBack(rt)
+			}
+		}
+
+		switch {
+		case r.Register != nil:
+			added(r.Register(router))
+		case r.All:
+			added(nameRoute(router.All(r.Path, handlers...), r.Name))
+		default:
+			for _, method := range r.Methods {
+				added(nameRoute(router.Add(method, r.Path, handlers...), r.Name))
+			}
+		}
+	}
+
+	if opts.readinessPath != "" {
+		app.Get(opts.readinessPath, params.readiness.Handler())
+	}
+
+	if opts.metricsPath != "" {
+		gatherer := params.gatherer
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+
+		app.Get(opts.metricsPath, httpmetrics.Handler(gatherer))
+	}
+
+	if opts.routeTablePath != "" {
+		app.Get(opts.routeTablePath, func(c *fiber.Ctx) error {
+			return c.JSON(infos)
+		})
+	}
+
+	if opts.logRoutes {
+		logRoutes(params.logger, opts.logRoutesLevel, appName, infos)
+	}
+
+	return app, nil
+}
+
+// nameRoute names the route registered last on the router
+func nameRoute(router fiber.Router, name string) fiber.Router {
+	if name == "" {
+		return router
+	}
+
+	return router.Name(name)
+}
+
 func (c *routerCallbacks) Add(prefix string, cb func(fiber.Router)) {
 	c.cbs = append(c.cbs, routerCallback{
 		Prefix:   prefix,
@@ -198,9 +284,3 @@ func (c *routerCallbacks) Add(prefix string, cb func(fiber.Router)) {
 func (c *routerCallbacks) Get() []routerCallback {
 	return c.cbs
 }
-
-func (c *routerCallbacks) Find(prefix string) (routerCallback, bool) {
-	return lo.Find(c.cbs, func(item routerCallback) bool {
-		return item.Prefix == prefix
-	})
-}
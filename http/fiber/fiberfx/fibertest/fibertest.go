@@ -0,0 +1,182 @@
+// Package fibertest provides helpers for testing fiber apps: a request builder,
+// response decoding, zerolog capture and an fxtest backed app factory.
+package fibertest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+)
+
+type (
+	// RequestBuilder builds requests sent to a fiber app with app.Test
+	RequestBuilder struct {
+		t       testing.TB
+		body    io.Reader
+		headers http.Header
+		query   url.Values
+		method  string
+		path    string
+	}
+
+	// Response is the response of the app with the body read
+	Response struct {
+		*http.Response
+		t    testing.TB
+		body []byte
+	}
+)
+
+// NewApp creates the fx app with the options, starts it and returns the fiber
+// app named appName. The fx app is stopped when the test finishes.
+func NewApp(t testing.TB, appName string, options ...fx.Option) *fiber.App {
+	t.Helper()
+
+	var fiberApp *fiber.App
+
+	app := fxtest.New(
+		t,
+		append(options, fx.Invoke(fx.Annotate(
+			func(a *fiber.App) {
+				fiberApp = a
+			},
+			fx.ParamTags(fiberfx.GetFiberApp(appName)),
+		)))...,
+	)
+
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	return fiberApp
+}
+
+func NewRequest(t testing.TB, method, path string) *RequestBuilder {
+	return &RequestBuilder{
+		t:       t,
+		method:  method,
+		path:    path,
+		headers: make(http.Header),
+		query:   make(url.Values),
+	}
+}
+
+func Get(t testing.TB, path string) *RequestBuilder {
+	return NewRequest(t, http.MethodGet, path)
+}
+
+func Post(t testing.TB, path string) *RequestBuilder {
+	return NewRequest(t, http.MethodPost, path)
+}
+
+func Put(t testing.TB, path string) *RequestBuilder {
+	return NewRequest(t, http.MethodPut, path)
+}
+
+func Patch(t testing.TB, path string) *RequestBuilder {
+	return NewRequest(t, http.MethodPatch, path)
+}
+
+func Delete(t testing.TB, path string) *RequestBuilder {
+	return NewRequest(t, http.MethodDelete, path)
+}
+
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.headers.Add(key, value)
+	return b
+}
+
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// Body sets the body of the request with its content type
+func (b *RequestBuilder) Body(body io.Reader, contentType string) *RequestBuilder {
+	b.body = body
+	b.headers.Set(fiber.HeaderContentType, contentType)
+
+	return b
+}
+
+// JSON encodes the value as the body of the request
+func (b *RequestBuilder) JSON(value any) *RequestBuilder {
+	b.t.Helper()
+
+	data, err := json.Marshal(value)
+	require.NoError(b.t, err)
+
+	return b.Body(bytes.NewReader(data), fiber.MIMEApplicationJSON)
+}
+
+func (b *RequestBuilder) Build() *http.Request {
+	target := b.path
+	if len(b.query) > 0 {
+		target += "?" + b.query.Encode()
+	}
+
+	req := httptest.NewRequest(b.method, target, b.body)
+
+	for key, values := range b.headers {
+		req.Header[key] = values
+	}
+
+	return req
+}
+
+// Do sends the request to the app, failing the test on errors
+func (b *RequestBuilder) Do(app *fiber.App) *Response {
+	b.t.Helper()
+
+	resp, err := app.Test(b.Build(), -1)
+	require.NoError(b.t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(b.t, err)
+	require.NoError(b.t, resp.Body.Close())
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return &Response{Response: resp, t: b.t, body: body}
+}
+
+func (r *Response) Bytes() []byte {
+	return r.body
+}
+
+func (r *Response) String() string {
+	return string(r.body)
+}
+
+// ContentType returns the Content-Type header of the response
+func (r *Response) ContentType() string {
+	return r.Header.Get(fiber.HeaderContentType)
+}
+
+// JSON decodes the body into value, failing the test on errors
+func (r *Response) JSON(value any) {
+	r.t.Helper()
+
+	require.NoError(r.t, json.Unmarshal(r.body, value), "body: %s", r.body)
+}
+
+// DecodeJSON decodes the body of the response into T
+func DecodeJSON[T any](r *Response) T {
+	r.t.Helper()
+
+	var value T
+
+	r.JSON(&value)
+
+	return value
+}
@@ -0,0 +1,62 @@
+package fibertest_test
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Put("/echo", func(c *fiber.Ctx) error {
+		var body map[string]string
+
+		if err := c.BodyParser(&body); err != nil {
+			return err
+		}
+
+		return c.JSON(fiber.Map{
+			"body":   body,
+			"query":  c.Query("page"),
+			"header": c.Get("X-Test"),
+		})
+	})
+
+	res := fibertest.Put(t, "/echo").
+		Query("page", "2").
+		Header("X-Test", "value").
+		JSON(map[string]string{"name": "John"}).
+		Do(app)
+
+	require.Equal(t, fiber.StatusOK, res.StatusCode)
+	require.JSONEq(t, `{"body":{"name":"John"},"query":"2","header":"value"}`, res.String())
+
+	type echo struct {
+		Body  map[string]string `json:"body"`
+		Query string            `json:"query"`
+	}
+
+	require.Equal(t, echo{Body: map[string]string{"name": "John"}, Query: "2"}, fibertest.DecodeJSON[echo](res))
+}
+
+func TestLogCapture(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := fibertest.NewLogger(t, zerolog.InfoLevel)
+
+	logger.Debug().Msg("ignored")
+	logger.Info().Str("key", "value").Msg("first")
+	logger.Error().Msg("second")
+
+	require.Equal(t, []string{"first", "second"}, logs.Messages())
+	require.Equal(t, "value", logs.RequireEntry(zerolog.InfoLevel, "first")["key"])
+
+	logs.Reset()
+	logs.RequireEmpty()
+}
@@ -0,0 +1,93 @@
+package fibertest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// LogCapture records the entries written by a zerolog.Logger
+	LogCapture struct {
+		t       testing.TB
+		entries []LogEntry
+		mu      sync.Mutex
+	}
+
+	// LogEntry is a decoded log entry
+	LogEntry map[string]any
+)
+
+// NewLogger creates a logger writing to the returned LogCapture
+func NewLogger(t testing.TB, level zerolog.Level) (zerolog.Logger, *LogCapture) {
+	capture := &LogCapture{t: t}
+
+	return zerolog.New(capture).Level(level), capture
+}
+
+func (c *LogCapture) Write(p []byte) (int, error) {
+	var entry LogEntry
+
+	if err := json.Unmarshal(bytes.TrimSpace(p), &entry); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.entries = append(c.entries, entry)
+	c.mu.Unlock()
+
+	return len(p), nil
+}
+
+// Entries returns the captured entries
+func (c *LogCapture) Entries() []LogEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]LogEntry(nil), c.entries...)
+}
+
+// Messages returns the messages of the captured entries
+func (c *LogCapture) Messages() []string {
+	entries := c.Entries()
+	messages := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		msg, _ := entry[zerolog.MessageFieldName].(string)
+		messages = append(messages, msg)
+	}
+
+	return messages
+}
+
+func (c *LogCapture) Reset() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// RequireEntry fails the test unless an entry with the level and message was captured
+func (c *LogCapture) RequireEntry(level zerolog.Level, msg string) LogEntry {
+	c.t.Helper()
+
+	for _, entry := range c.Entries() {
+		if entry[zerolog.LevelFieldName] == level.String() && entry[zerolog.MessageFieldName] == msg {
+			return entry
+		}
+	}
+
+	require.Failf(c.t, "log entry not found", "level: %s, message: %q, captured: %v", level, msg, c.Messages())
+
+	return nil
+}
+
+// RequireEmpty fails the test if any entry was captured
+func (c *LogCapture) RequireEmpty() {
+	c.t.Helper()
+
+	require.Empty(c.t, c.Entries())
+}
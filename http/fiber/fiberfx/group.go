@@ -0,0 +1,182 @@
+package fiberfx
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+type (
+	// GroupOption configures a route group created with Group
+	GroupOption func(*groupOptions)
+
+	groupOptions struct {
+		routes      []RouteFx
+		middlewares []middlewareEntry
+		callbacks   []func(fiber.Router)
+	}
+
+	// routeGroup is the resolved representation of a group, consumed by App
+	routeGroup struct {
+		Prefix      string
+		Middlewares []fiber.Handler
+	}
+)
+
+// WithGroupRoutes adds routes (and nested groups) to the group
+func WithGroupRoutes(routes ...RouteFx) GroupOption {
+	return func(opts *groupOptions) {
+		opts.routes = append(opts.routes, routes...)
+	}
+}
+
+// WithGroupMiddleware adds middlewares that run for every route in the group and its subgroups
+func WithGroupMiddleware(middlewares ...fiber.Handler) GroupOption {
+	return func(opts *groupOptions) {
+		for _, m := range middlewares {
+			opts.middlewares = append(opts.middlewares, middlewareEntry{handler: m})
+		}
+	}
+}
+
+// WithGroupMiddlewareFx adds middleware constructors whose dependencies are injected by uberfx
+func WithGroupMiddlewareFx(middlewareFuncs ...RouteMiddlewareFunc) GroupOption {
+	return func(opts *groupOptions) {
+		for _, m := range middlewareFuncs {
+			opts.middlewares = append(opts.middlewares, middlewareEntry{ctor: m})
+		}
+	}
+}
+
+// WithGroupCallback adds a callback that is called once with the fiber.Router of the group
+func WithGroupCallback(cb func(fiber.Router)) GroupOption {
+	return func(opts *groupOptions) {
+		opts.callbacks = append(opts.callbacks, cb)
+	}
+}
+
+// Group creates a route group mounted under prefix. Groups can be nested
+// arbitrarily by passing them to WithGroupRoutes of another group, and they can
+// be used anywhere a RouteFx is accepted (e.g. Routes).
+func Group(prefix string, options ...GroupOption) RouteFx {
+	var opts groupOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return func(appName, parentPrefix string) fx.Option {
+		fullPrefix := joinPrefix(parentPrefix, prefix)
+
+		options, tags, merge := provideMiddlewares(appName, "group-"+fullPrefix, opts.middlewares)
+
+		options = append(options, fx.Provide(fx.Annotate(
+			handlersFunc(len(tags), func(resolved []fiber.Handler) routeGroup {
+				return routeGroup{
+					Prefix:      fullPrefix,
+					Middlewares: merge(resolved),
+				}
+			}),
+			fx.ParamTags(tags...),
+			fx.ResultTags(fiberGroups(appName)),
+		)))
+
+		if len(opts.callbacks) > 0 {
+			callbacks := opts.callbacks
+			options = append(options, fx.Invoke(fx.Annotate(
+				func(cbs *routerCallbacks) {
+					for _, cb := range callbacks {
+						cbs.Add(fullPrefix, cb)
+					}
+				},
+				fx.ParamTags(routerCallbacksName(appName)),
+			)))
+		}
+
+		for _, r := range opts.routes {
+			options = append(options, r(appName, fullPrefix))
+		}
+
+		return fx.Options(options...)
+	}
+}
+
+// joinPrefix joins a parent group prefix with a child prefix
+func joinPrefix(parent, child string) string {
+	child = strings.Trim(child, "/")
+	parent = strings.TrimSuffix(parent, "/")
+
+	if child == "" {
+		return parent
+	}
+
+	return parent + "/" + child
+}
+
+// routerTree creates every fiber group exactly once and hands out the same
+// fiber.Router for each prefix
+type routerTree struct {
+	app     *fiber.App
+	routers map[string]fiber.Router
+}
+
+func newRouterTree(app *fiber.App) *routerTree {
+	return &routerTree{
+		app:     app,
+		routers: make(map[string]fiber.Router),
+	}
+}
+
+func (t *routerTree) Get(prefix string) fiber.Router {
+	if prefix == "" {
+		return t.app
+	}
+
+	if router, ok := t.routers[prefix]; ok {
+		return router
+	}
+
+	router := t.app.Group(prefix)
+	t.routers[prefix] = router
+
+	return router
+}
+
+// handlersFunc builds a function with exactly count fiber.Handler parameters.
+// fx.Annotate treats a variadic parameter as a single slice, so a fixed arity
+// is required to apply one name tag per injected middleware.
+func handlersFunc[T any](count int, fn func([]fiber.Handler) T) any {
+	handlerType := reflect.TypeFor[fiber.Handler]()
+
+	in := make([]reflect.Type, count)
+	for i := range in {
+		in[i] = handlerType
+	}
+
+	funcType := reflect.FuncOf(in, []reflect.Type{reflect.TypeFor[T]()}, false)
+
+	return reflect.MakeFunc(funcType, func(args []reflect.Value) []reflect.Value {
+		handlers := make([]fiber.Handler, len(args))
+		for i, arg := range args {
+			handlers[i], _ = arg.Interface().(fiber.Handler)
+		}
+
+		return []reflect.Value{reflect.ValueOf(fn(handlers))}
+	}).Interface()
+}
+
+// sortGroups orders groups so parents are registered before their children,
+// which guarantees that parent middlewares run first
+func sortGroups(groups []routeGroup) []routeGroup {
+	sorted := make([]routeGroup, len(groups))
+	copy(sorted, groups)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i].Prefix, "/") < strings.Count(sorted[j].Prefix, "/")
+	})
+
+	return sorted
+}
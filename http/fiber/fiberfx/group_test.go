@@ -0,0 +1,144 @@
+package fiberfx_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// tagMiddleware appends a value to the X-Trace response header
+func tagMiddleware(value string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Append("X-Trace", value)
+		return c.Next()
+	}
+}
+
+// TestGroup tests nested route groups
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	type TestDep struct {
+		Value string
+	}
+
+	var callbackCalls atomic.Int32
+
+	routes := fiberfx.Routes([]fiberfx.RouteFx{
+		fiberfx.Group("/api",
+			fiberfx.WithGroupMiddleware(tagMiddleware("api")),
+			fiberfx.WithGroupCallback(func(_ fiber.Router) {
+				callbackCalls.Add(1)
+			}),
+			fiberfx.WithGroupRoutes(
+				fiberfx.Get("/health", fiberfx.RouteTestHandler),
+				fiberfx.Group("/v1",
+					fiberfx.WithGroupMiddlewareFx(func(dep TestDep) fiber.Handler {
+						return tagMiddleware(dep.Value)
+					}),
+					fiberfx.WithGroupRoutes(
+						fiberfx.Group("/users",
+							fiberfx.WithGroupMiddleware(tagMiddleware("users")),
+							fiberfx.WithGroupRoutes(
+								fiberfx.Get("/", fiberfx.RouteTestHandler),
+								fiberfx.Get("/:id", fiberfx.RouteTestHandler),
+							),
+						),
+					),
+				),
+			),
+		),
+	})
+
+	fiberApp := fibertest.NewApp(t, "groupapp",
+		fx.Supply(TestDep{Value: "v1"}),
+		fiberfx.App("groupapp", routes),
+	)
+
+	t.Run("middlewares of all levels run in order", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/api/v1/users/5", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "api, v1, users", resp.Header.Get("X-Trace"))
+	})
+
+	t.Run("route on the group itself", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/api/health", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "api", resp.Header.Get("X-Trace"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "Test", string(body))
+	})
+
+	t.Run("callback is called once", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, int32(1), callbackCalls.Load())
+	})
+}
+
+// TestGroupWithPrefix tests that groups are nested under the WithPrefix of Routes
+func TestGroupWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	routes := fiberfx.Routes([]fiberfx.RouteFx{
+		fiberfx.Group("/v2/", fiberfx.WithGroupRoutes(
+			fiberfx.Get("/items", fiberfx.RouteTestHandler),
+		)),
+	}, fiberfx.WithPrefix("/api"))
+
+	fiberApp := fibertest.NewApp(t, "prefixgroupapp", fiberfx.App("prefixgroupapp", routes))
+
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/api/v2/items", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// TestRouterCallbacksCalledOnce tests that every callback of a prefix is called exactly once
+func TestRouterCallbacksCalledOnce(t *testing.T) {
+	t.Parallel()
+
+	var first, second atomic.Int32
+
+	routes := fiberfx.CombineRoutes(
+		fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/a", fiberfx.RouteTestHandler),
+			fiberfx.Get("/b", fiberfx.RouteTestHandler),
+		}, fiberfx.WithPrefix("/api"), fiberfx.WithRouterCallback(func(_ fiber.Router) {
+			first.Add(1)
+		})),
+		fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/c", fiberfx.RouteTestHandler),
+		}, fiberfx.WithPrefix("/api"), fiberfx.WithRouterCallback(func(_ fiber.Router) {
+			second.Add(1)
+		})),
+	)
+
+	fiberApp := fibertest.NewApp(t, "callbackapp", fiberfx.App("callbackapp", routes))
+
+	for _, path := range []string{"/api/a", "/api/b", "/api/c"} {
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, strings.TrimPrefix(path, "/api"))
+	}
+
+	require.Equal(t, int32(1), first.Load())
+	require.Equal(t, int32(1), second.Load())
+}
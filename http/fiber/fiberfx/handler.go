@@ -1,19 +1,31 @@
 package fiberfx
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+var annotationCounter atomic.Uint64
 
 func GetFiberApp(appName string) string {
 	return fmt.Sprintf(`name:"fiber-%s"`, appName)
 }
 
-func fiberHandlers(appName, method, prefix, path string) string {
-	return fmt.Sprintf(`name:"fiber-handler-%s-%s-%s-%s"`, appName, method, prefix, path)
+// nextAnnotationID returns a process-wide unique ID used to build fx names
+// that must not collide between registrations
+func nextAnnotationID() string {
+	return strconv.FormatUint(annotationCounter.Add(1), 10)
 }
 
 func fiberHandlerRoutes(appName string) string {
 	return fmt.Sprintf(`group:"fiber-handlers-%s"`, appName)
 }
 
+func fiberGroups(appName string) string {
+	return fmt.Sprintf(`group:"fiber-groups-%s"`, appName)
+}
+
 func routerCallbacksName(appName string) string {
 	return fmt.Sprintf(`name:"fiber-%s-router-callbacks"`, appName)
 }
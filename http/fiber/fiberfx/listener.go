@@ -0,0 +1,218 @@
+package fiberfx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"go.uber.org/multierr"
+
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd socket activation
+const listenFdsStart = 3
+
+var (
+	ErrNotSocketActivated = errors.New("process is not socket activated")
+	ErrNoSystemdListener  = errors.New("no socket activated listener found")
+)
+
+// Listener creates the listeners the app is served on. It is called when the
+// fx app starts, so errors fail the start.
+type Listener func(ctx context.Context) ([]net.Listener, error)
+
+// WithListeners serves the app on the listeners in addition to the addr
+// passed to RunApp. When addr is empty, only these listeners are used.
+func WithListeners(listeners ...Listener) RunOption {
+	return func(opts *runOptions) {
+		opts.listeners = append(opts.listeners, listeners...)
+	}
+}
+
+// TCPListener listens on the TCP address
+func TCPListener(addr string) Listener {
+	return func(ctx context.Context) ([]net.Listener, error) {
+		var lc net.ListenConfig
+
+		ln, err := lc.Listen(ctx, "tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+
+		return []net.Listener{ln}, nil
+	}
+}
+
+// UnixListener listens on the Unix domain socket at path. A stale socket left
+// by a previous process, which refuses connections, is removed; a socket still
+// served by another process fails with syscall.EADDRINUSE. The permissions of
+// the socket are set to mode when it is not zero. The socket is removed when
+// the listener is closed.
+func UnixListener(path string, mode fs.FileMode) Listener {
+	return func(ctx context.Context) ([]net.Listener, error) {
+		if info, err := os.Stat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+			if err := removeStaleSocket(ctx, path); err != nil {
+				return nil, err
+			}
+		}
+
+		var lc net.ListenConfig
+
+		ln, err := lc.Listen(ctx, "unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+		}
+
+		if mode != 0 {
+			if err := os.Chmod(path, mode); err != nil {
+				_ = ln.Close()
+				return nil, fmt.Errorf("failed to set mode of socket %s: %w", path, err)
+			}
+		}
+
+		return []net.Listener{ln}, nil
+	}
+}
+
+// removeStaleSocket removes the socket at path only when connecting to it is
+// refused, so a socket served by another process is never taken over
+func removeStaleSocket(ctx context.Context, path string) error {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to listen on %s: %w", path, syscall.EADDRINUSE)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("failed to check socket %s: %w", path, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// SystemdListener uses the sockets passed by systemd socket activation
+// (LISTEN_FDS). With names, only the sockets with a matching FileDescriptorName
+// are used, otherwise all passed sockets are used.
+func SystemdListener(names ...string) Listener {
+	return func(context.Context) ([]net.Listener, error) {
+		files, err := systemdFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		var listeners []net.Listener
+
+		for _, file := range files {
+			if len(names) > 0 && !slices.Contains(names, file.Name()) {
+				continue
+			}
+
+			// FileListener duplicates the descriptor, so the listener can be
+			// created again after the app is restarted
+			ln, err := net.FileListener(file)
+			if err != nil {
+				return nil, multierr.Append(
+					fmt.Errorf("failed to use socket %s: %w", file.Name(), err),
+					closeListeners(listeners),
+				)
+			}
+
+			listeners = append(listeners, ln)
+		}
+
+		if len(listeners) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNoSystemdListener, strings.Join(names, ", "))
+		}
+
+		return listeners, nil
+	}
+}
+
+// TLSListener serves the listeners over TLS, see WithTLS
+func TLSListener(listener Listener, cfg corehttp.TLSConfig) Listener {
+	return func(ctx context.Context) ([]net.Listener, error) {
+		tlsConfig, err := corehttp.NewTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		listeners, err := listener(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, ln := range listeners {
+			listeners[i] = tls.NewListener(ln, tlsConfig)
+		}
+
+		return listeners, nil
+	}
+}
+
+var systemdFiles = sync.OnceValues(func() ([]*os.File, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, ErrNotSocketActivated
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, ErrNotSocketActivated
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	files := make([]*os.File, 0, count)
+
+	for i := range count {
+		// systemd names sockets without FileDescriptorName "unknown"
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		files = append(files, os.NewFile(uintptr(listenFdsStart+i), name))
+	}
+
+	return files, nil
+})
+
+func openListeners(ctx context.Context, listeners []Listener) ([]net.Listener, error) {
+	var opened []net.Listener
+
+	for _, listener := range listeners {
+		lns, err := listener(ctx)
+		if err != nil {
+			return nil, multierr.Append(err, closeListeners(opened))
+		}
+
+		opened = append(opened, lns...)
+	}
+
+	return opened, nil
+}
+
+func closeListeners(listeners []net.Listener) error {
+	var err error
+
+	for _, ln := range listeners {
+		err = multierr.Append(err, ln.Close())
+	}
+
+	return err
+}
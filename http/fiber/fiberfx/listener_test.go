@@ -0,0 +1,141 @@
+package fiberfx_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+)
+
+// TestRunAppMultipleListeners tests serving the app on TCP and a Unix socket at once
+func TestRunAppMultipleListeners(t *testing.T) {
+	t.Parallel()
+
+	// Unix socket paths are limited in length, so t.TempDir may be too long
+	dir, err := os.MkdirTemp("", "fiberfx")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "admin.sock")
+
+	// A stale socket left by a previous process is replaced
+	stale, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	var lc net.ListenConfig
+
+	// Reserve a free port for the TCP listener
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	app := fxtest.New(
+		t,
+		fiberfx.App("multiapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/test", fiberfx.RouteTestHandler),
+		})),
+		fiberfx.RunApp(addr, "multiapp", time.Second,
+			fiberfx.WithListeners(fiberfx.UnixListener(socket, 0o600)),
+		),
+	)
+
+	app.RequireStart()
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+
+	for _, test := range []struct {
+		client *http.Client
+		url    string
+	}{
+		{client: http.DefaultClient, url: "http://" + addr + "/test"},
+		{client: unixClient, url: "http://unix/test"},
+	} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, test.url, nil)
+		require.NoError(t, err)
+
+		resp, err := test.client.Do(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "Test", string(body))
+	}
+
+	app.RequireStop()
+
+	_, err = os.Stat(socket)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestRunAppWithoutListeners tests that RunApp fails without an address or listeners
+func TestRunAppWithoutListeners(t *testing.T) {
+	t.Parallel()
+
+	app := fxtest.New(
+		t,
+		fiberfx.App("nolisteners", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/test", fiberfx.RouteTestHandler),
+		})),
+		fiberfx.RunApp("", "nolisteners", time.Second),
+	)
+
+	require.ErrorContains(t, app.Start(t.Context()), "no address or listeners")
+}
+
+// TestSystemdListenerNotActivated tests that the systemd listener fails outside socket activation
+func TestSystemdListenerNotActivated(t *testing.T) {
+	t.Parallel()
+
+	_, err := fiberfx.SystemdListener()(t.Context())
+	require.ErrorIs(t, err, fiberfx.ErrNotSocketActivated)
+}
+
+// TestUnixListenerSocketInUse tests that a socket served by another process is not taken over
+func TestUnixListenerSocketInUse(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "fiberfx")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "busy.sock")
+
+	var lc net.ListenConfig
+
+	busy, err := lc.Listen(t.Context(), "unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = busy.Close() })
+
+	_, err = fiberfx.UnixListener(socket, 0)(t.Context())
+	require.ErrorIs(t, err, syscall.EADDRINUSE)
+
+	// The socket of the other process is kept
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
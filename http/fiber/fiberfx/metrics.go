@@ -0,0 +1,40 @@
+package fiberfx
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/httpmetrics"
+)
+
+// WithMetricsEndpoint exposes the metrics of the prometheus.Gatherer from the
+// container (e.g. metricsfx.Module) on GET path, falling back to the default
+// gatherer of the prometheus package. It can be used on a separate app served
+// on an internal address.
+//
+// Requests of every app are observed when metricsfx.HTTPMetrics is provided.
+// See Metrics.
+func WithMetricsEndpoint(path string) Option {
+	return func(opts *appOptions) {
+		opts.metricsPath = path
+	}
+}
+
+// Metrics observes every request of the app, labelled by the route template,
+// e.g. "/users/:id", with its final status, see fiber.NextHandled.
+//
+// App registers it before the recover middleware when *httpmetrics.Metrics
+// is provided, e.g. with metricsfx.Module.
+func Metrics(m *httpmetrics.Metrics, appName string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		_ = corehttp.NextHandled(c)
+
+		m.Observe(appName, c.Method(), c.Route().Path, c.Response().StatusCode(), time.Since(start))
+
+		return nil
+	}
+}
@@ -1,6 +1,8 @@
 package fiberfx
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 )
@@ -30,6 +32,12 @@ type (
 	// RouteMiddlewareFunc represents a function that returns a Fiber middleware
 	// This allows middleware to have dependencies injected by uberfx
 	RouteMiddlewareFunc any
+
+	// middlewareEntry is either a plain middleware or a constructor resolved by uberfx
+	middlewareEntry struct {
+		handler fiber.Handler
+		ctor    RouteMiddlewareFunc
+	}
 )
 
 // RegisterMiddleware registers a middleware to be used with a specific app
@@ -121,3 +129,40 @@ func applyMiddlewares(app *fiber.App, middlewares []middlewareWithPrefix) {
 		}
 	}
 }
+
+// provideMiddlewares registers the constructors of the entries with unique name tags.
+// It returns the options, the tags to inject the resolved handlers with, and a
+// function merging the resolved handlers with plain middlewares in declaration order.
+func provideMiddlewares(appName, kind string, entries []middlewareEntry) ([]fx.Option, []string, func([]fiber.Handler) []fiber.Handler) {
+	id := nextAnnotationID()
+	options := make([]fx.Option, 0, len(entries)+1)
+	tags := make([]string, 0, len(entries))
+
+	for i, entry := range entries {
+		if entry.ctor == nil {
+			continue
+		}
+
+		tag := fiberMiddlewareTag(appName, kind+"-"+id+"-"+strconv.Itoa(i))
+		tags = append(tags, tag)
+		options = append(options, fx.Provide(fx.Annotate(entry.ctor, fx.ResultTags(tag))))
+	}
+
+	merge := func(resolved []fiber.Handler) []fiber.Handler {
+		handlers := make([]fiber.Handler, 0, len(entries))
+
+		for _, entry := range entries {
+			if entry.ctor == nil {
+				handlers = append(handlers, entry.handler)
+				continue
+			}
+
+			handlers = append(handlers, resolved[0])
+			resolved = resolved[1:]
+		}
+
+		return handlers
+	}
+
+	return options, tags, merge
+}
@@ -1,12 +1,22 @@
 package fiberfx
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+)
 
 type (
 	appOptions struct {
 		afterCreate    func(app *fiber.App)
+		accessLog      []AccessLogOption
+		routeTablePath string
+		metricsPath    string
+		readinessPath  string
 		cfg            fiber.Config
+		logRoutesLevel zerolog.Level
 		useMiddlewares bool
+		logRoutes      bool
+		useAccessLog   bool
 	}
 
 	Option func(opts *appOptions)
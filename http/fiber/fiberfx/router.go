@@ -5,7 +5,6 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
-	"go.uber.org/fx"
 )
 
 func Get(path string, handler any) RouteFx {
@@ -128,160 +127,205 @@ func DeleteWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router),
 	return RouteWithMiddlewareFx(http.MethodDelete, path, cb, middlewareFuncs, handler)
 }
 
+func Head(path string, handler any) RouteFx {
+	return Route(http.MethodHead, path, handler)
+}
+
+func HeadWithRouterCallback(path string, cb func(fiber.Router), handler any) RouteFx {
+	return RouteWithRouterCallback(http.MethodHead, path, cb, handler)
+}
+
+func HeadWithMiddleware(path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodHead, path, nil, middlewares, handler)
+}
+
+func HeadWithRouterCallbackAndMiddleware(path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodHead, path, cb, middlewares, handler)
+}
+
+func HeadWithMiddlewareFx(path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodHead, path, nil, middlewareFuncs, handler)
+}
+
+func HeadWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodHead, path, cb, middlewareFuncs, handler)
+}
+
+func Options(path string, handler any) RouteFx {
+	return Route(http.MethodOptions, path, handler)
+}
+
+func OptionsWithRouterCallback(path string, cb func(fiber.Router), handler any) RouteFx {
+	return RouteWithRouterCallback(http.MethodOptions, path, cb, handler)
+}
+
+func OptionsWithMiddleware(path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodOptions, path, nil, middlewares, handler)
+}
+
+func OptionsWithRouterCallbackAndMiddleware(path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodOptions, path, cb, middlewares, handler)
+}
+
+func OptionsWithMiddlewareFx(path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodOptions, path, nil, middlewareFuncs, handler)
+}
+
+func OptionsWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodOptions, path, cb, middlewareFuncs, handler)
+}
+
+func Connect(path string, handler any) RouteFx {
+	return Route(http.MethodConnect, path, handler)
+}
+
+func ConnectWithRouterCallback(path string, cb func(fiber.Router), handler any) RouteFx {
+	return RouteWithRouterCallback(http.MethodConnect, path, cb, handler)
+}
+
+func ConnectWithMiddleware(path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodConnect, path, nil, middlewares, handler)
+}
+
+func ConnectWithRouterCallbackAndMiddleware(path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodConnect, path, cb, middlewares, handler)
+}
+
+func ConnectWithMiddlewareFx(path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodConnect, path, nil, middlewareFuncs, handler)
+}
+
+func ConnectWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodConnect, path, cb, middlewareFuncs, handler)
+}
+
+func Trace(path string, handler any) RouteFx {
+	return Route(http.MethodTrace, path, handler)
+}
+
+func TraceWithRouterCallback(path string, cb func(fiber.Router), handler any) RouteFx {
+	return RouteWithRouterCallback(http.MethodTrace, path, cb, handler)
+}
+
+func TraceWithMiddleware(path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodTrace, path, nil, middlewares, handler)
+}
+
+func TraceWithRouterCallbackAndMiddleware(path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return RouteWithMiddleware(http.MethodTrace, path, cb, middlewares, handler)
+}
+
+func TraceWithMiddlewareFx(path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodTrace, path, nil, middlewareFuncs, handler)
+}
+
+func TraceWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return RouteWithMiddlewareFx(http.MethodTrace, path, cb, middlewareFuncs, handler)
+}
+
+// All registers the handler for every request method of the app
+func All(path string, handler any) RouteFx {
+	return ALL(path).Handler(handler)
+}
+
+func AllWithRouterCallback(path string, cb func(fiber.Router), handler any) RouteFx {
+	return ALL(path).Callback(cb).Handler(handler)
+}
+
+func AllWithMiddleware(path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return ALL(path).Use(middlewares...).Handler(handler)
+}
+
+func AllWithRouterCallbackAndMiddleware(path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return ALL(path).Callback(cb).Use(middlewares...).Handler(handler)
+}
+
+func AllWithMiddlewareFx(path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return ALL(path).UseFx(middlewareFuncs...).Handler(handler)
+}
+
+func AllWithRouterCallbackAndMiddlewareFx(path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return ALL(path).Callback(cb).UseFx(middlewareFuncs...).Handler(handler)
+}
+
+// Match registers the handler for each of the given request methods
+func Match(methods []string, path string, handler any) RouteFx {
+	return MATCH(methods, path).Handler(handler)
+}
+
+func MatchWithRouterCallback(methods []string, path string, cb func(fiber.Router), handler any) RouteFx {
+	return MATCH(methods, path).Callback(cb).Handler(handler)
+}
+
+func MatchWithMiddleware(methods []string, path string, middlewares []fiber.Handler, handler any) RouteFx {
+	return MATCH(methods, path).Use(middlewares...).Handler(handler)
+}
+
+func MatchWithRouterCallbackAndMiddleware(methods []string, path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
+	return MATCH(methods, path).Callback(cb).Use(middlewares...).Handler(handler)
+}
+
+func MatchWithMiddlewareFx(methods []string, path string, middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return MATCH(methods, path).UseFx(middlewareFuncs...).Handler(handler)
+}
+
+func MatchWithRouterCallbackAndMiddlewareFx(methods []string, path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
+	return MATCH(methods, path).Callback(cb).UseFx(middlewareFuncs...).Handler(handler)
+}
+
 func Route(method, path string, handler any) RouteFx {
 	return RouteWithRouterCallback(method, path, nil, handler)
 }
 
-type route struct {
-	Handler     fiber.Handler
-	CallBack    func(fiber.Router)
-	Prefix      string
-	Method      string
-	Path        string
-	Middlewares []fiber.Handler
-}
+type (
+	route struct {
+		Handler  fiber.Handler
+		CallBack func(fiber.Router)
+		// Register replaces the default registration, used by static files and mounted apps
+		Register    func(fiber.Router) fiber.Router
+		Prefix      string
+		Path        string
+		Source      string
+		Kind        string
+		Name        string
+		Metadata    map[string]any
+		Methods     []string
+		Middlewares []fiber.Handler
+		All         bool
+	}
+
+	// routeSpec describes which requests a route matches
+	routeSpec struct {
+		path    string
+		methods []string
+		all     bool
+	}
+)
 
 func RouteWithRouterCallback(method, path string, cb func(fiber.Router), handler any) RouteFx {
 	return RouteWithMiddleware(method, path, cb, nil, handler)
 }
 
 func RouteWithMiddleware(method, path string, cb func(fiber.Router), middlewares []fiber.Handler, handler any) RouteFx {
-	return func(appName, prefix string) fx.Option {
-		// Create a wrapper function that adapts the handler to a fiber.Handler
-		handlerWrapper := func() fiber.Handler {
-			// Convert the handler to a fiber.Handler
-			if h, ok := handler.(fiber.Handler); ok {
-				return h
-			}
-			// If it's already a fiber.Handler function, return it directly
-			if h, ok := handler.(func(*fiber.Ctx) error); ok {
-				return h
-			}
-			// If it's a function that returns a fiber.Handler, call it to get the handler
-			if h, ok := handler.(func() fiber.Handler); ok {
-				return h()
-			}
-			// If it's a function that returns a fiber.Handler function, call it to get the handler
-			if h, ok := handler.(func() func(*fiber.Ctx) error); ok {
-				return h()
-			}
-			// Otherwise, panic as we can't handle this type
-			panic(fmt.Sprintf("handler must be a fiber.Handler or func(*fiber.Ctx) error, got %T", handler))
-		}
-
- 	// Call handlerWrapper to get the actual handler
-	handler := handlerWrapper()
-
-	return fx.Provide(
-			// Provide the handler directly
-			fx.Annotate(
-				func() fiber.Handler {
-					return handler
-				},
-				fx.ResultTags(fiberHandlers(appName, method, prefix, path)),
-			),
-			// Create the route with the handler
-			fx.Annotate(
-				func(handler fiber.Handler) route {
-					return route{
-						Prefix:      prefix,
-						Method:      method,
-						Path:        path,
-						Handler:     handler,
-						CallBack:    cb,
-						Middlewares: middlewares,
-					}
-				},
-				fx.ParamTags(fiberHandlers(appName, method, prefix, path)),
-				fx.ResultTags(fiberHandlerRoutes(appName)),
-			),
-		)
-	}
+	return NewRoute(method, path).Callback(cb).Use(middlewares...).Handler(handler)
 }
 
 // RouteWithMiddlewareFx is similar to RouteWithMiddleware but allows middleware functions
 // to have dependencies injected by uberfx
 func RouteWithMiddlewareFx(method, path string, cb func(fiber.Router), middlewareFuncs []RouteMiddlewareFunc, handler any) RouteFx {
-	return func(appName, prefix string) fx.Option {
-		// Create a wrapper function that adapts the handler to a fiber.Handler
-		handlerWrapper := func() fiber.Handler {
-			// Convert the handler to a fiber.Handler
-			if h, ok := handler.(fiber.Handler); ok {
-				return h
-			}
-			// If it's already a fiber.Handler function, return it directly
-			if h, ok := handler.(func(*fiber.Ctx) error); ok {
-				return h
-			}
-			// If it's a function that returns a fiber.Handler, call it to get the handler
-			if h, ok := handler.(func() fiber.Handler); ok {
-				return h()
-			}
-			// If it's a function that returns a fiber.Handler function, call it to get the handler
-			if h, ok := handler.(func() func(*fiber.Ctx) error); ok {
-				return h()
-			}
-			// Otherwise, panic as we can't handle this type
-			panic(fmt.Sprintf("handler must be a fiber.Handler or func(*fiber.Ctx) error, got %T", handler))
-		}
-
-		// Call handlerWrapper to get the actual handler
-		handler := handlerWrapper()
-
-		// Create options for registering the handler
-		handlerOption := fx.Provide(
-			fx.Annotate(
-				func() fiber.Handler {
-					return handler
-				},
-				fx.ResultTags(fiberHandlers(appName, method, prefix, path)),
-			),
-		)
-
-		// Create options for registering each middleware
-		middlewareOptions := make([]fx.Option, 0, len(middlewareFuncs))
-		for i, middlewareFunc := range middlewareFuncs {
-			middlewareTag := fiberMiddlewareTag(appName, method+"-"+prefix+"-"+path+"-"+fmt.Sprintf("%d", i))
-			middlewareOptions = append(middlewareOptions, fx.Provide(
-				fx.Annotate(
-					middlewareFunc,
-					fx.ResultTags(middlewareTag),
-				),
-			))
-		}
-
-		// Create the route with placeholders for middleware handlers
-		routeOption := fx.Provide(
-			fx.Annotate(
-				func(handler fiber.Handler, middlewares ...fiber.Handler) route {
-					return route{
-						Prefix:      prefix,
-						Method:      method,
-						Path:        path,
-						Handler:     handler,
-						CallBack:    cb,
-						Middlewares: middlewares,
-					}
-				},
-				fx.ParamTags(
-					append(
-						[]string{fiberHandlers(appName, method, prefix, path)},
-						generateMiddlewareTags(appName, method, prefix, path, len(middlewareFuncs))...,
-					)...,
-				),
-				fx.ResultTags(fiberHandlerRoutes(appName)),
-			),
-		)
-
-		return fx.Options(append(append([]fx.Option{handlerOption}, middlewareOptions...), routeOption)...)
-	}
-}
-
-// generateMiddlewareTags generates tags for middleware functions
-func generateMiddlewareTags(appName, method, prefix, path string, count int) []string {
-	tags := make([]string, count)
-	for i := 0; i < count; i++ {
-		tags[i] = fiberMiddlewareTag(appName, method+"-"+prefix+"-"+path+"-"+fmt.Sprintf("%d", i))
+	return NewRoute(method, path).Callback(cb).UseFx(middlewareFuncs...).Handler(handler)
+}
+
+// toFiberHandler adapts the supported handler shapes to a fiber.Handler
+func toFiberHandler(handler any) fiber.Handler {
+	switch h := handler.(type) {
+	case fiber.Handler:
+		return h
+	// If it's a function that returns a fiber.Handler, call it to get the handler
+	case func() fiber.Handler:
+		return h()
+	default:
+		// Otherwise, panic as we can't handle this type
+		panic(fmt.Sprintf("handler must be a fiber.Handler or func(*fiber.Ctx) error, got %T", handler))
 	}
-	return tags
 }
@@ -0,0 +1,354 @@
+package fiberfx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/multierr"
+)
+
+const (
+	// MethodAll is reported for routes registered with All
+	MethodAll = "ALL"
+	// MethodStatic is reported for static file routes
+	MethodStatic = "STATIC"
+	// MethodMount is reported for mounted fiber apps
+	MethodMount = "MOUNT"
+)
+
+var (
+	ErrDuplicateRoute = errors.New("duplicate route")
+	ErrAmbiguousRoute = errors.New("ambiguous route")
+	ErrInvalidMethod  = errors.New("invalid route method")
+)
+
+// RouteInfo describes a route registered on a fiberfx app
+type RouteInfo struct {
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Method   string         `json:"method"`
+	Path     string         `json:"path"`
+	Name     string         `json:"name,omitempty"`
+	Source   string         `json:"source"`
+}
+
+const packagePath = "github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx."
+
+// callerLocation returns file:line of the first caller outside of this package,
+// which is where the route was declared
+func callerLocation() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, packagePath) {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// fullPath joins a group prefix and a route path the same way fiber does
+func fullPath(prefix, path string) string {
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+
+	if prefix == "" {
+		return path
+	}
+
+	return strings.TrimRight(prefix, "/") + path
+}
+
+func routeInfos(handlers []route) []RouteInfo {
+	infos := make([]RouteInfo, 0, len(handlers))
+
+	for _, r := range handlers {
+		info := RouteInfo{
+			Path:     fullPath(r.Prefix, r.Path),
+			Name:     r.Name,
+			Metadata: r.Metadata,
+			Source:   r.Source,
+		}
+
+		switch {
+		case r.Register != nil:
+			info.Method = r.Kind
+		case r.All:
+			info.Method = MethodAll
+		default:
+			for _, method := range r.Methods {
+				info.Method = method
+				infos = append(infos, info)
+			}
+
+			continue
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// canonicalPath normalizes a path according to the case sensitivity and strict
+// routing settings of fiber
+func canonicalPath(path string, cfg fiber.Config) string {
+	if !cfg.CaseSensitive {
+		path = strings.ToLower(path)
+	}
+
+	if !cfg.StrictRouting && len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+
+	return path
+}
+
+// routePattern replaces parameter names, so routes matching the same requests
+// produce the same pattern. Constraints are kept, e.g. :id<int> becomes
+// :<int>, so parameters only match the same requests with equal constraints.
+func routePattern(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			if end := strings.IndexAny(segment, "<?"); end >= 0 {
+				segments[i] = ":" + segment[end:]
+			} else {
+				segments[i] = ":"
+			}
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// sortRoutes orders routes so that, at the first segment where their paths
+// differ in kind, static segments are registered before parameters and
+// parameters before optional or constrained parameters and wildcards. Static
+// files and mounted apps match whole prefixes and sort like wildcards. Routes
+// of the same kinds are ordered by path and source, so the registration order
+// does not depend on the order of the fx value group, e.g. /users/me is
+// always registered before /users/:id.
+func sortRoutes(routes []route) []route {
+	type sortedRoute struct {
+		path  string
+		ranks []int
+		route route
+	}
+
+	entries := make([]sortedRoute, 0, len(routes))
+
+	for _, r := range routes {
+		path := fullPath(r.Prefix, r.Path)
+		ranked := path
+
+		if r.Register != nil {
+			ranked = strings.TrimRight(path, "/") + "/*"
+		}
+
+		segments := strings.Split(ranked, "/")
+		ranks := make([]int, len(segments))
+
+		for i, segment := range segments {
+			ranks[i] = segmentRank(segment)
+		}
+
+		entries = append(entries, sortedRoute{path: path, ranks: ranks, route: r})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if c := slices.Compare(a.ranks, b.ranks); c != 0 {
+			return c < 0
+		}
+
+		if a.path != b.path {
+			return a.path < b.path
+		}
+
+		return a.route.Source < b.route.Source
+	})
+
+	sorted := make([]route, 0, len(entries))
+
+	for _, e := range entries {
+		sorted = append(sorted, e.route)
+	}
+
+	return sorted
+}
+
+// segmentRank ranks a path segment for sortRoutes: 0 for static segments,
+// 1 for required parameters without constraints, e.g. ":id", and 2 for
+// everything else
+func segmentRank(segment string) int {
+	if !strings.ContainsAny(segment, ":*+") {
+		return 0
+	}
+
+	name, ok := strings.CutPrefix(segment, ":")
+	if !ok || name == "" {
+		return 2
+	}
+
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// requestMethods returns the request methods accepted by the app
+func requestMethods(cfg fiber.Config) []string {
+	if len(cfg.RequestMethods) == 0 {
+		return fiber.DefaultMethods
+	}
+
+	return cfg.RequestMethods
+}
+
+// validateMethods reports routes without any method, e.g. MATCH with an
+// empty list, and routes with methods the app does not accept, which fiber
+// would panic on
+func validateMethods(routes []route, cfg fiber.Config) error {
+	var err error
+
+	methods := requestMethods(cfg)
+
+	for _, r := range routes {
+		if r.Register != nil || r.All {
+			continue
+		}
+
+		path := fullPath(r.Prefix, r.Path)
+
+		if len(r.Methods) == 0 {
+			err = multierr.Append(err, fmt.Errorf("%w: %s (%s) has no methods", ErrInvalidMethod, path, r.Source))
+			continue
+		}
+
+		for _, method := range r.Methods {
+			if !slices.Contains(methods, strings.ToUpper(method)) {
+				err = multierr.Append(err, fmt.Errorf("%w: %s %s (%s)", ErrInvalidMethod, method, path, r.Source))
+			}
+		}
+	}
+
+	return err
+}
+
+// validateRoutes reports routes registered more than once and parametrized
+// routes that can never be reached because an equivalent route exists
+func validateRoutes(infos []RouteInfo, cfg fiber.Config) error {
+	type routeKey struct {
+		method  string
+		pattern string
+	}
+
+	var err error
+
+	seen := make(map[routeKey]RouteInfo, len(infos))
+	methods := requestMethods(cfg)
+	expanded := make([]RouteInfo, 0, len(infos))
+
+	for _, info := range infos {
+		switch info.Method {
+		case MethodStatic, MethodMount:
+			// Static files and mounted apps match whole prefixes, they are not validated
+		case MethodAll:
+			for _, method := range methods {
+				expanded = append(expanded, RouteInfo{Method: method, Path: info.Path, Source: info.Source})
+			}
+		default:
+			expanded = append(expanded, info)
+		}
+	}
+
+	for _, info := range expanded {
+		path := canonicalPath(info.Path, cfg)
+		key := routeKey{method: info.Method, pattern: routePattern(path)}
+
+		prev, exists := seen[key]
+		if !exists {
+			seen[key] = info
+			continue
+		}
+
+		if canonicalPath(prev.Path, cfg) == path {
+			err = multierr.Append(err, fmt.Errorf(
+				"%w: %s %s registered at %s and %s",
+				ErrDuplicateRoute, info.Method, info.Path, prev.Source, info.Source,
+			))
+
+			continue
+		}
+
+		err = multierr.Append(err, fmt.Errorf(
+			"%w: %s %s (%s) conflicts with %s (%s)",
+			ErrAmbiguousRoute, info.Method, info.Path, info.Source, prev.Path, prev.Source,
+		))
+	}
+
+	return err
+}
+
+// logRoutes writes the route table
+func logRoutes(logger zerolog.Logger, level zerolog.Level, appName string, infos []RouteInfo) {
+	logger = loggerOrGlobal(logger)
+
+	for _, info := range infos {
+		logger.WithLevel(level).
+			Str("app", appName).
+			Str("method", info.Method).
+			Str("path", info.Path).
+			Str("source", info.Source).
+			Msg("Registered route")
+	}
+}
+
+// loggerOrGlobal returns the global logger when the optional zerolog.Logger
+// was not provided in the container
+func loggerOrGlobal(logger zerolog.Logger) zerolog.Logger {
+	if reflect.ValueOf(logger).IsZero() {
+		return log.Logger
+	}
+
+	return logger
+}
+
+// WithRouteLogging logs every route of the app on the given level when the app is created.
+// The zerolog.Logger from the container is used (e.g. loggerfx.ZerologModule) if available.
+func WithRouteLogging(level zerolog.Level) Option {
+	return func(opts *appOptions) {
+		opts.logRoutes = true
+		opts.logRoutesLevel = level
+	}
+}
+
+// WithRouteTableEndpoint exposes the route table as JSON on GET path
+func WithRouteTableEndpoint(path string) Option {
+	return func(opts *appOptions) {
+		opts.routeTablePath = path
+	}
+}
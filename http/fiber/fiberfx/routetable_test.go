@@ -0,0 +1,178 @@
+package fiberfx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestRouteValidation tests detection of duplicate and ambiguous routes
+func TestRouteValidation(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(routes fiberfx.RoutesFx) *fx.App {
+		return fx.New(
+			fx.NopLogger,
+			fiberfx.App("validationapp", routes),
+			fx.Invoke(fx.Annotate(func(*fiber.App) {}, fx.ParamTags(fiberfx.GetFiberApp("validationapp")))),
+		)
+	}
+
+	t.Run("duplicate route across modules", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(fiberfx.CombineRoutes(
+			fiberfx.Routes([]fiberfx.RouteFx{fiberfx.Get("/users", fiberfx.RouteTestHandler)}, fiberfx.WithPrefix("/api")),
+			fiberfx.Routes([]fiberfx.RouteFx{
+				fiberfx.Group("/api", fiberfx.WithGroupRoutes(fiberfx.Get("/users", fiberfx.RouteTestHandler))),
+			}),
+		))
+
+		require.ErrorIs(t, app.Err(), fiberfx.ErrDuplicateRoute)
+		require.ErrorContains(t, app.Err(), "GET /api/users registered at")
+		require.ErrorContains(t, app.Err(), "routetable_test.go")
+	})
+
+	t.Run("ambiguous parametrized route", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/users/:id", fiberfx.RouteTestHandler),
+			fiberfx.Get("/users/:name", fiberfx.RouteTestHandler),
+		}))
+
+		require.ErrorIs(t, app.Err(), fiberfx.ErrAmbiguousRoute)
+	})
+
+	t.Run("ambiguous constrained route", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/items/:id<int>", fiberfx.RouteTestHandler),
+			fiberfx.Get("/items/:number<int>", fiberfx.RouteTestHandler),
+		}))
+
+		require.ErrorIs(t, app.Err(), fiberfx.ErrAmbiguousRoute)
+	})
+
+	t.Run("different constraints", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/items/:id<int>", fiberfx.RouteTestHandler),
+			fiberfx.Get("/items/:slug<alpha>", fiberfx.RouteTestHandler),
+			fiberfx.Get("/items/:code<int>?", fiberfx.RouteTestHandler),
+		}))
+
+		require.NoError(t, app.Err())
+	})
+
+	t.Run("same path with different methods", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/users/:id", fiberfx.RouteTestHandler),
+			fiberfx.Delete("/users/:id", fiberfx.RouteTestHandler),
+			fiberfx.Get("/users/:id/posts", fiberfx.RouteTestHandler),
+		}))
+
+		require.NoError(t, app.Err())
+	})
+}
+
+// TestRouteOrder tests that static segments are matched before parameters,
+// whatever the order the routes are declared and provided in
+func TestRouteOrder(t *testing.T) {
+	t.Parallel()
+
+	handler := func(body string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			return c.SendString(body)
+		}
+	}
+
+	// fx provides the routes in random order, so the app is created several times
+	for range 10 {
+		app := fibertest.NewApp(t, "orderapp",
+			fiberfx.App("orderapp", fiberfx.Routes([]fiberfx.RouteFx{
+				fiberfx.Get("/*", handler("wildcard")),
+				fiberfx.Get("/users/:id", handler("user")),
+				fiberfx.Get("/users/:id/posts", handler("user posts")),
+				fiberfx.Get("/users/me/posts", handler("my posts")),
+				fiberfx.Get("/users/me", handler("me")),
+			})),
+		)
+
+		require.Equal(t, "me", fibertest.Get(t, "/users/me").Do(app).String())
+		require.Equal(t, "user", fibertest.Get(t, "/users/5").Do(app).String())
+		require.Equal(t, "my posts", fibertest.Get(t, "/users/me/posts").Do(app).String())
+		require.Equal(t, "user posts", fibertest.Get(t, "/users/5/posts").Do(app).String())
+		require.Equal(t, "wildcard", fibertest.Get(t, "/other").Do(app).String())
+	}
+}
+
+// TestRouteTable tests logging and exposing the route table
+func TestRouteTable(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	fiberApp := fibertest.NewApp(t, "tableapp",
+		fx.Supply(zerolog.New(&buf)),
+		fiberfx.App("tableapp",
+			fiberfx.Routes([]fiberfx.RouteFx{
+				fiberfx.Get("/users", fiberfx.RouteTestHandler),
+				fiberfx.Post("/users", fiberfx.RouteTestHandler),
+			}, fiberfx.WithPrefix("/api")),
+			fiberfx.WithRouteLogging(zerolog.InfoLevel),
+			fiberfx.WithRouteTableEndpoint("/debug/routes"),
+		),
+	)
+
+	require.Contains(t, buf.String(), `"method":"GET","path":"/api/users"`)
+	require.Contains(t, buf.String(), `"method":"POST","path":"/api/users"`)
+
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var infos []fiberfx.RouteInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&infos))
+	require.Len(t, infos, 2)
+	require.ElementsMatch(t, []string{http.MethodGet, http.MethodPost}, []string{infos[0].Method, infos[1].Method})
+	require.Contains(t, infos[0].Source, "routetable_test.go")
+}
+
+// TestRouteWithMultipleMiddlewareFx tests that every injected middleware is applied to the route
+func TestRouteWithMultipleMiddlewareFx(t *testing.T) {
+	t.Parallel()
+
+	type TestDep struct {
+		Value string
+	}
+
+	fiberApp := fibertest.NewApp(t, "multimwapp",
+		fx.Supply(TestDep{Value: "dep"}),
+		fiberfx.App("multimwapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.GetWithMiddlewareFx("/test", []fiberfx.RouteMiddlewareFunc{
+				func(dep TestDep) fiber.Handler { return tagMiddleware(dep.Value + "-1") },
+				func(dep TestDep) fiber.Handler { return tagMiddleware(dep.Value + "-2") },
+			}, fiberfx.RouteTestHandler),
+		})),
+	)
+
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "dep-1, dep-2", resp.Header.Get("X-Trace"))
+}
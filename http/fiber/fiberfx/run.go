@@ -0,0 +1,170 @@
+package fiberfx
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+type (
+	// RunOption configures how RunApp serves the app
+	RunOption func(*runOptions)
+
+	runOptions struct {
+		tls              *corehttp.TLSConfig
+		listeners        []Listener
+		preShutdownDelay time.Duration
+	}
+
+	// Readiness reports whether the app is ready to receive traffic.
+	// RunApp marks the app ready once it is listening and not ready
+	// as soon as it starts shutting down.
+	Readiness struct {
+		ready atomic.Bool
+	}
+)
+
+// GetReadiness returns the fx tag of the *Readiness of the app
+func GetReadiness(appName string) string {
+	return fmt.Sprintf(`name:"fiber-%s-readiness"`, appName)
+}
+
+// WithPreShutdownDelay waits for the delay after the app is marked not ready and
+// before the server is shut down, so load balancers can stop sending traffic
+func WithPreShutdownDelay(delay time.Duration) RunOption {
+	return func(opts *runOptions) {
+		opts.preShutdownDelay = delay
+	}
+}
+
+// WithTLS serves the addr passed to RunApp over TLS. Setting ClientCAFile enables
+// mutual TLS, the verified client certificate is available through
+// fiber.ClientCertificate. Certificates are reloaded when the files change on disk.
+// Use TLSListener for listeners added with WithListeners.
+func WithTLS(cfg corehttp.TLSConfig) RunOption {
+	return func(opts *runOptions) {
+		opts.tls = &cfg
+	}
+}
+
+// WithReadinessEndpoint exposes the readiness of the app on GET path,
+// responding with 200 when ready and 503 otherwise
+func WithReadinessEndpoint(path string) Option {
+	return func(opts *appOptions) {
+		opts.readinessPath = path
+	}
+}
+
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Handler responds with 200 when the app is ready and 503 otherwise
+func (r *Readiness) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if r.Ready() {
+			return c.SendStatus(fiber.StatusOK)
+		}
+
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	}
+}
+
+// RunApp serves the app on addr and the listeners added with WithListeners.
+// The listeners are bound when the fx app starts, so errors like an address
+// already in use fail the start. Errors while serving on any listener shut
+// down the fx app with a non-zero exit code.
+func RunApp(addr, appName string, shutdownTimeout time.Duration, options ...RunOption) fx.Option {
+	var opts runOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	listeners := opts.listeners
+
+	if addr != "" {
+		listener := TCPListener(addr)
+		if opts.tls != nil {
+			listener = TLSListener(listener, *opts.tls)
+		}
+
+		listeners = append([]Listener{listener}, listeners...)
+	}
+
+	return fx.Invoke(fx.Annotate(func(
+		app *fiber.App,
+		readiness *Readiness,
+		lc fx.Lifecycle,
+		shutdowner fx.Shutdowner,
+		logger zerolog.Logger,
+	) {
+		logger = loggerOrGlobal(logger)
+
+		var stopping atomic.Bool
+
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				if len(listeners) == 0 {
+					return fmt.Errorf("fiber app %s: no address or listeners to serve on", appName)
+				}
+
+				lns, err := openListeners(ctx, listeners)
+				if err != nil {
+					return fmt.Errorf("fiber app %s: %w", appName, err)
+				}
+
+				for _, ln := range lns {
+					go func() {
+						if err := app.Listener(ln); err != nil && !stopping.Load() {
+							logger.Error().
+								Err(err).
+								Str("app", appName).
+								Str("addr", ln.Addr().String()).
+								Msg("Fiber HTTP Server failed")
+
+							_ = shutdowner.Shutdown(fx.ExitCode(1))
+						}
+					}()
+				}
+
+				readiness.SetReady(true)
+
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				stopping.Store(true)
+				readiness.SetReady(false)
+
+				if opts.preShutdownDelay > 0 {
+					select {
+					case <-time.After(opts.preShutdownDelay):
+					case <-ctx.Done():
+					}
+				}
+
+				newCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+				defer cancel()
+
+				return app.ShutdownWithContext(newCtx)
+			},
+		})
+	}, fx.ParamTags(
+		GetFiberApp(appName),
+		GetReadiness(appName),
+		``,
+		``,
+		`optional:"true"`,
+	)))
+}
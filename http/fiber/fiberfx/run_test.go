@@ -0,0 +1,127 @@
+package fiberfx_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestRunAppAddressInUse tests that failing to bind the listener fails the start of the fx app
+func TestRunAppAddressInUse(t *testing.T) {
+	t.Parallel()
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	app := fxtest.New(
+		t,
+		fiberfx.App("inuseapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/test", fiberfx.RouteTestHandler),
+		})),
+		fiberfx.RunApp(ln.Addr().String(), "inuseapp", 100*time.Millisecond),
+	)
+
+	err = app.Start(t.Context())
+	require.ErrorContains(t, err, "failed to listen on "+ln.Addr().String())
+}
+
+// TestRunAppReadiness tests readiness while starting and stopping the app
+func TestRunAppReadiness(t *testing.T) {
+	t.Parallel()
+
+	var readiness *fiberfx.Readiness
+
+	fiberApp := fibertest.NewApp(t, "readyapp",
+		fiberfx.App("readyapp",
+			fiberfx.Routes([]fiberfx.RouteFx{
+				fiberfx.Get("/test", fiberfx.RouteTestHandler),
+			}),
+			fiberfx.WithReadinessEndpoint("/readyz"),
+		),
+		fx.Invoke(fx.Annotate(func(r *fiberfx.Readiness) {
+			readiness = r
+		}, fx.ParamTags(fiberfx.GetReadiness("readyapp")))),
+	)
+
+	// RunApp is not used, so the app never becomes ready
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	readiness.SetReady(true)
+
+	resp, err = fiberApp.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// TestRunAppPreShutdownDelay tests that the app is not ready during the pre-shutdown delay
+func TestRunAppPreShutdownDelay(t *testing.T) {
+	t.Parallel()
+
+	const delay = 200 * time.Millisecond
+
+	var readiness *fiberfx.Readiness
+
+	app := fxtest.New(
+		t,
+		fiberfx.App("delayapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/test", fiberfx.RouteTestHandler),
+		})),
+		fiberfx.RunApp("127.0.0.1:0", "delayapp", time.Second, fiberfx.WithPreShutdownDelay(delay)),
+		fx.Invoke(fx.Annotate(func(r *fiberfx.Readiness) {
+			readiness = r
+		}, fx.ParamTags(fiberfx.GetReadiness("delayapp")))),
+	)
+
+	require.NoError(t, app.Start(t.Context()))
+	require.True(t, readiness.Ready())
+
+	stopped := make(chan error, 1)
+	start := time.Now()
+
+	go func() {
+		stopped <- app.Stop(t.Context())
+	}()
+
+	require.Eventually(t, func() bool {
+		return !readiness.Ready()
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, <-stopped)
+	require.GreaterOrEqual(t, time.Since(start), delay)
+}
+
+// TestRunAppInvalidTLS tests that an invalid TLS configuration fails the start of the fx app
+func TestRunAppInvalidTLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	app := fxtest.New(
+		t,
+		fiberfx.App("tlsapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/test", fiberfx.RouteTestHandler),
+		})),
+		fiberfx.RunApp("127.0.0.1:0", "tlsapp", 100*time.Millisecond, fiberfx.WithTLS(corehttp.TLSConfig{
+			CertFile: filepath.Join(dir, "missing.crt"),
+			KeyFile:  filepath.Join(dir, "missing.key"),
+		})),
+	)
+
+	require.ErrorContains(t, app.Start(t.Context()), "fiber app tlsapp")
+}
@@ -0,0 +1,86 @@
+package fiberfx
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	"go.uber.org/fx"
+)
+
+// Static serves files from the root directory under prefix
+func Static(prefix, root string, config ...fiber.Static) RouteFx {
+	return newRegisterRoute(MethodStatic, prefix, func(router fiber.Router) fiber.Router {
+		return router.Static(prefix, root, config...)
+	})
+}
+
+// StaticFS serves files from fsys (e.g. embed.FS) under prefix. The Root of the
+// optional config is always replaced with fsys.
+func StaticFS(prefix string, fsys fs.FS, config ...filesystem.Config) RouteFx {
+	var cfg filesystem.Config
+
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	cfg.Root = http.FS(fsys)
+
+	return newRegisterRoute(MethodStatic, prefix, func(router fiber.Router) fiber.Router {
+		return router.Use(prefix, filesystem.New(cfg))
+	})
+}
+
+// Mount attaches an existing fiber app under prefix
+func Mount(prefix string, subApp *fiber.App) RouteFx {
+	return newRegisterRoute(MethodMount, prefix, func(router fiber.Router) fiber.Router {
+		return router.Mount(prefix, subApp)
+	})
+}
+
+// MountApp attaches the fiberfx app registered with App(subAppName, ...) under prefix
+func MountApp(prefix, subAppName string) RouteFx {
+	source := callerLocation()
+
+	return func(appName, groupPrefix string) fx.Option {
+		return fx.Provide(
+			fx.Annotate(
+				func(subApp *fiber.App) route {
+					return route{
+						Prefix: groupPrefix,
+						Path:   prefix,
+						Source: source,
+						Kind:   MethodMount,
+						Register: func(router fiber.Router) fiber.Router {
+							return router.Mount(prefix, subApp)
+						},
+					}
+				},
+				fx.ParamTags(GetFiberApp(subAppName)),
+				fx.ResultTags(fiberHandlerRoutes(appName)),
+			),
+		)
+	}
+}
+
+func newRegisterRoute(kind, path string, register func(fiber.Router) fiber.Router) RouteFx {
+	source := callerLocation()
+
+	return func(appName, prefix string) fx.Option {
+		return fx.Provide(
+			fx.Annotate(
+				func() route {
+					return route{
+						Prefix:   prefix,
+						Path:     path,
+						Source:   source,
+						Kind:     kind,
+						Register: register,
+					}
+				},
+				fx.ResultTags(fiberHandlerRoutes(appName)),
+			),
+		)
+	}
+}
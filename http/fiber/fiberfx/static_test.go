@@ -0,0 +1,131 @@
+package fiberfx_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestAdditionalMethods tests HEAD, OPTIONS, CONNECT, TRACE, All and Match routes
+func TestAdditionalMethods(t *testing.T) {
+	t.Parallel()
+
+	fiberApp := fibertest.NewApp(t, "methodsapp", fiberfx.App("methodsapp", fiberfx.Routes([]fiberfx.RouteFx{
+		fiberfx.Head("/head", fiberfx.RouteTestHandler),
+		fiberfx.Options("/options", fiberfx.RouteTestHandler),
+		fiberfx.Connect("/connect", fiberfx.RouteTestHandler),
+		fiberfx.Trace("/trace", fiberfx.RouteTestHandler),
+		fiberfx.All("/all", fiberfx.RouteTestHandler),
+		fiberfx.Match([]string{http.MethodGet, http.MethodPost}, "/match", fiberfx.RouteTestHandler),
+	})))
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: http.MethodHead, path: "/head", status: http.StatusOK},
+		{method: http.MethodOptions, path: "/options", status: http.StatusOK},
+		{method: http.MethodConnect, path: "/connect", status: http.StatusOK},
+		{method: http.MethodTrace, path: "/trace", status: http.StatusOK},
+		{method: http.MethodGet, path: "/all", status: http.StatusOK},
+		{method: http.MethodDelete, path: "/all", status: http.StatusOK},
+		{method: http.MethodGet, path: "/match", status: http.StatusOK},
+		{method: http.MethodPost, path: "/match", status: http.StatusOK},
+		{method: http.MethodPut, path: "/match", status: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := fiberApp.Test(httptest.NewRequest(test.method, test.path, nil))
+			require.NoError(t, err)
+			require.Equal(t, test.status, resp.StatusCode)
+		})
+	}
+}
+
+// TestAllConflictsWithMethodRoute tests that All is validated against every request method
+func TestAllConflictsWithMethodRoute(t *testing.T) {
+	t.Parallel()
+
+	app := fx.New(
+		fx.NopLogger,
+		fiberfx.App("allconflictapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.All("/resource", fiberfx.RouteTestHandler),
+			fiberfx.Get("/resource", fiberfx.RouteTestHandler),
+		})),
+		fx.Invoke(fx.Annotate(func(*fiber.App) {}, fx.ParamTags(fiberfx.GetFiberApp("allconflictapp")))),
+	)
+
+	require.ErrorIs(t, app.Err(), fiberfx.ErrDuplicateRoute)
+}
+
+// TestStaticAndMount tests static files, embedded file systems and mounted apps
+func TestStaticAndMount(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("from disk"), 0o600))
+
+	fsys := fstest.MapFS{
+		"index.html": &fstest.MapFile{Data: []byte("from fs")},
+	}
+
+	subApp := fiber.New()
+	subApp.Get("/ping", func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+
+	fiberApp := fibertest.NewApp(t, "staticapp",
+		fiberfx.App("adminapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/status", func(c *fiber.Ctx) error {
+				return c.SendString("admin")
+			}),
+		})),
+		fiberfx.App("staticapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Static("/files", dir),
+			fiberfx.StaticFS("/assets", fsys),
+			fiberfx.Mount("/sub", subApp),
+			fiberfx.Group("/internal", fiberfx.WithGroupRoutes(
+				fiberfx.MountApp("/admin", "adminapp"),
+			)),
+		})),
+	)
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{path: "/files/file.txt", body: "from disk"},
+		{path: "/assets/", body: "from fs"},
+		{path: "/sub/ping", body: "pong"},
+		{path: "/internal/admin/status", body: "admin"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, test.path, nil))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, test.body, string(body))
+		})
+	}
+}
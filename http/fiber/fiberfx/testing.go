@@ -55,22 +55,24 @@ func RouteTestHandler() fiber.Handler {
 
 // ExportedRoute is an exported version of the route struct for testing
 type ExportedRoute struct {
-	Method      string
+	Methods     []string
 	Path        string
 	Prefix      string
 	HasCallback bool
 	HasHandler  bool
+	All         bool
 	Middlewares int
 }
 
 // CreateExportedRoute creates an ExportedRoute from a route
 func CreateExportedRoute(r route) ExportedRoute {
 	return ExportedRoute{
-		Method:      r.Method,
+		Methods:     r.Methods,
 		Path:        r.Path,
 		Prefix:      r.Prefix,
 		HasCallback: r.CallBack != nil,
 		HasHandler:  r.Handler != nil,
+		All:         r.All,
 		Middlewares: len(r.Middlewares),
 	}
 }
@@ -0,0 +1,92 @@
+package fiberfx
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx/tracing"
+)
+
+// Tracing starts a server span for every request, continuing the trace of the
+// traceparent header. Spans are named after the method and the route template,
+// e.g. "GET /users/:id", once the route has been matched. The span is stored in
+// the user context and its traceparent is used as the correlation traceparent,
+// so logs of the request carry its trace ID.
+//
+// Errors of the handlers are recorded on the span together with the final
+// status, see fiber.NextHandled.
+//
+// App registers it before every other middleware, including the recover
+// middleware and the afterCreate middlewares, when a trace.TracerProvider is
+// provided, e.g. with tracingfx.Module. Panics recovered in the handlers are
+// recorded with status 500.
+func Tracing(tp trace.TracerProvider) fiber.Handler {
+	tracer := tp.Tracer(tracing.InstrumentationName)
+	propagator := tracing.Propagator()
+
+	return func(c *fiber.Ctx) error {
+		ctx := propagator.Extract(c.UserContext(), headerCarrier{c: c})
+
+		ctx, span := tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+
+		if traceParent := tracing.TraceParent(span.SpanContext()); traceParent != "" {
+			ctx = correlation.WithTraceParent(ctx, traceParent)
+		}
+
+		c.SetUserContext(ctx)
+
+		if err := corehttp.NextHandled(c); err != nil {
+			span.RecordError(err)
+		}
+
+		status := c.Response().StatusCode()
+		route := c.Route().Path
+
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+
+		return nil
+	}
+}
+
+// headerCarrier adapts the request and response headers to propagation.TextMapCarrier
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+
+	return keys
+}
@@ -0,0 +1,121 @@
+package fiberfx_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx"
+)
+
+// TestTracing tests the server spans created when a TracerProvider is provided
+func TestTracing(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var exporter *tracetest.InMemoryExporter
+
+	logger, logs := fibertest.NewLogger(t, zerolog.DebugLevel)
+
+	app := fibertest.NewApp(t, "tracingapp",
+		fx.Supply(logger),
+		tracingfx.InMemoryModule("tracing-test"),
+		fx.Populate(&exporter),
+		fiberfx.App("tracingapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Get("/users/:id", func(c *fiber.Ctx) error {
+				span := trace.SpanFromContext(c.UserContext())
+				require.True(t, span.IsRecording())
+				require.Equal(t, tracingfx.TraceParent(span.SpanContext()), correlation.TraceParent(c.UserContext()))
+
+				return c.SendString("user")
+			}),
+			fiberfx.Get("/fail", func(*fiber.Ctx) error {
+				return errors.New("failed")
+			}),
+			fiberfx.Get("/panic", func(*fiber.Ctx) error {
+				panic("failed")
+			}),
+		}), fiberfx.WithAccessLog(), fiberfx.WithAfterCreate(func(app *fiber.App) {
+			app.Use(func(c *fiber.Ctx) error {
+				c.Set("X-Recording", strconv.FormatBool(trace.SpanFromContext(c.UserContext()).IsRecording()))
+				return c.Next()
+			})
+		})),
+	)
+
+	t.Run("continues the trace", func(t *testing.T) {
+		exporter.Reset()
+
+		res := fibertest.Get(t, "/users/5").Header(correlation.HeaderTraceParent, traceParent).Do(app)
+		require.Equal(t, fiber.StatusOK, res.StatusCode)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+
+		span := spans[0]
+		require.Equal(t, "GET /users/:id", span.Name)
+		require.Equal(t, trace.SpanKindServer, span.SpanKind)
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+		require.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+		require.True(t, span.Parent.IsRemote())
+		require.Contains(t, span.Attributes, semconv.HTTPRoute("/users/:id"))
+		require.Contains(t, span.Attributes, semconv.HTTPResponseStatusCode(fiber.StatusOK))
+		require.Equal(t, codes.Unset, span.Status.Code)
+		require.Equal(t, "true", res.Header.Get("X-Recording"))
+
+		entry := logs.RequireEntry(zerolog.InfoLevel, "Request")
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry[correlation.LogFieldTraceID])
+	})
+
+	t.Run("starts a trace", func(t *testing.T) {
+		exporter.Reset()
+		logs.Reset()
+
+		res := fibertest.Get(t, "/users/5").Do(app)
+		require.Equal(t, fiber.StatusOK, res.StatusCode)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		require.False(t, spans[0].Parent.IsValid())
+
+		entry := logs.RequireEntry(zerolog.InfoLevel, "Request")
+		require.Equal(t, spans[0].SpanContext.TraceID().String(), entry[correlation.LogFieldTraceID])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exporter.Reset()
+
+		res := fibertest.Get(t, "/fail").Do(app)
+		require.Equal(t, fiber.StatusInternalServerError, res.StatusCode)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		require.Equal(t, "GET /fail", spans[0].Name)
+		require.Equal(t, codes.Error, spans[0].Status.Code)
+		require.Contains(t, spans[0].Attributes, semconv.HTTPResponseStatusCode(fiber.StatusInternalServerError))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		exporter.Reset()
+
+		res := fibertest.Get(t, "/panic").Do(app)
+		require.Equal(t, fiber.StatusInternalServerError, res.StatusCode)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		require.Equal(t, "GET /panic", spans[0].Name)
+		require.Equal(t, codes.Error, spans[0].Status.Code)
+		require.Contains(t, spans[0].Attributes, semconv.HTTPResponseStatusCode(fiber.StatusInternalServerError))
+	})
+}
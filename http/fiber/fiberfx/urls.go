@@ -0,0 +1,168 @@
+package fiberfx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/multierr"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/constants"
+)
+
+var (
+	ErrRouteNotFound      = errors.New("route not found")
+	ErrMissingRouteParam  = errors.New("missing route parameter")
+	ErrDuplicateRouteName = errors.New("duplicate route name")
+)
+
+// URLBuilder generates paths of named routes, including the prefixes of their groups.
+// It is populated when the fiber app is created.
+type URLBuilder struct {
+	routes map[string]string
+	mu     sync.RWMutex
+}
+
+// GetURLBuilder returns the fx tag of the *URLBuilder for the app
+func GetURLBuilder(appName string) string {
+	return fmt.Sprintf(`name:"fiber-%s-urls"`, appName)
+}
+
+// URLs returns the URLBuilder of the app handling the request
+func URLs(c *fiber.Ctx) *URLBuilder {
+	urls, _ := c.Locals(constants.URLBuilderContextKey).(*URLBuilder)
+
+	return urls
+}
+
+// For generates the path of the named route. Params are formatted with fmt.Sprint,
+// wildcards are replaced using "*" and "+" as keys.
+func (u *URLBuilder) For(name string, params fiber.Map) (string, error) {
+	u.mu.RLock()
+	path, ok := u.routes[name]
+	u.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+
+	return buildPath(name, path, params)
+}
+
+// MustFor is like For, but panics if the path cannot be generated
+func (u *URLBuilder) MustFor(name string, params fiber.Map) string {
+	path, err := u.For(name, params)
+	if err != nil {
+		panic(err)
+	}
+
+	return path
+}
+
+// set replaces the routes of the builder, reporting names used for different paths
+func (u *URLBuilder) set(infos []RouteInfo) error {
+	var err error
+
+	routes := make(map[string]string, len(infos))
+
+	for _, info := range infos {
+		if info.Name == "" {
+			continue
+		}
+
+		if path, exists := routes[info.Name]; exists && path != info.Path {
+			err = multierr.Append(err, fmt.Errorf("%w: %s is used for %s and %s (%s)", ErrDuplicateRouteName, info.Name, path, info.Path, info.Source))
+
+			continue
+		}
+
+		routes[info.Name] = info.Path
+	}
+
+	u.mu.Lock()
+	u.routes = routes
+	u.mu.Unlock()
+
+	return err
+}
+
+// buildPath replaces parameters (:name, :name?, :name<constraint>) and
+// wildcards (*, +) of the route path
+//
+//nolint:gocognit
+func buildPath(name, path string, params fiber.Map) (string, error) {
+	var sb strings.Builder
+
+	sb.Grow(len(path))
+
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+
+		switch ch {
+		case ':':
+			end := i + 1
+			for end < len(path) && !strings.ContainsRune("/-.:?+*<", rune(path[end])) {
+				end++
+			}
+
+			key := path[i+1 : end]
+
+			// Skip the constraint up to its matching >
+			if end < len(path) && path[end] == '<' {
+				for depth := 0; end < len(path); end++ {
+					if path[end] == '<' {
+						depth++
+					} else if path[end] == '>' {
+						depth--
+					}
+
+					if depth == 0 {
+						end++
+						break
+					}
+				}
+			}
+
+			optional := end < len(path) && path[end] == '?'
+
+			if optional {
+				end++
+			}
+
+			value, ok := params[key]
+
+			switch {
+			case ok:
+				sb.WriteString(url.PathEscape(fmt.Sprint(value)))
+			case optional:
+				// Drop the separator of the missing optional segment
+				trimmed := strings.TrimSuffix(sb.String(), "/")
+				sb.Reset()
+				sb.WriteString(trimmed)
+			default:
+				return "", fmt.Errorf("%w: %s of route %s", ErrMissingRouteParam, key, name)
+			}
+
+			i = end - 1
+		case '*', '+':
+			value, ok := params[string(ch)]
+
+			if ok {
+				sb.WriteString(fmt.Sprint(value))
+			} else if ch == '+' {
+				return "", fmt.Errorf("%w: %c of route %s", ErrMissingRouteParam, ch, name)
+			}
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "/", nil
+	}
+
+	return sb.String(), nil
+}
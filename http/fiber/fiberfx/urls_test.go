@@ -0,0 +1,113 @@
+package fiberfx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+// TestURLBuilder tests generating paths of named routes
+func TestURLBuilder(t *testing.T) {
+	t.Parallel()
+
+	var urls *fiberfx.URLBuilder
+
+	fiberApp := fibertest.NewApp(t, "urlsapp",
+		fiberfx.App("urlsapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.Group("/v1", fiberfx.WithGroupRoutes(
+				fiberfx.GET("/users/:id").Name("user.show").Handler(fiberfx.RouteTestHandler),
+				fiberfx.POST("/users").Name("user.create").Handler(func(c *fiber.Ctx) error {
+					location, err := fiberfx.URLs(c).For("user.show", fiber.Map{"id": 10})
+					if err != nil {
+						return err
+					}
+
+					c.Location(location)
+
+					return c.SendStatus(fiber.StatusCreated)
+				}),
+				fiberfx.GET("/flights/:from-:to").Name("flights").Handler(fiberfx.RouteTestHandler),
+				fiberfx.GET("/posts/:slug?").Name("posts").Handler(fiberfx.RouteTestHandler),
+				fiberfx.GET("/files/*").Name("files").Handler(fiberfx.RouteTestHandler),
+				fiberfx.GET("/orders/:id<int>").Name("order.show").Handler(fiberfx.RouteTestHandler),
+				fiberfx.GET("/reports/:date<regex(\\d{4}-\\d{2})>/:page<int;min(1)>?").Name("reports").Handler(fiberfx.RouteTestHandler),
+			)),
+		}, fiberfx.WithPrefix("/api"))),
+		fx.Invoke(fx.Annotate(func(u *fiberfx.URLBuilder) {
+			urls = u
+		}, fx.ParamTags(fiberfx.GetURLBuilder("urlsapp")))),
+	)
+
+	tests := []struct {
+		params fiber.Map
+		name   string
+		want   string
+	}{
+		{name: "user.show", params: fiber.Map{"id": 5}, want: "/api/v1/users/5"},
+		{name: "user.show", params: fiber.Map{"id": "a b"}, want: "/api/v1/users/a%20b"},
+		{name: "user.create", want: "/api/v1/users"},
+		{name: "flights", params: fiber.Map{"from": "LHR", "to": "BEG"}, want: "/api/v1/flights/LHR-BEG"},
+		{name: "posts", params: fiber.Map{"slug": "hello"}, want: "/api/v1/posts/hello"},
+		{name: "posts", want: "/api/v1/posts"},
+		{name: "files", params: fiber.Map{"*": "css/app.css"}, want: "/api/v1/files/css/app.css"},
+		{name: "order.show", params: fiber.Map{"id": 5}, want: "/api/v1/orders/5"},
+		{name: "reports", params: fiber.Map{"date": "2026-10", "page": 2}, want: "/api/v1/reports/2026-10/2"},
+		{name: "reports", params: fiber.Map{"date": "2026-10"}, want: "/api/v1/reports/2026-10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			t.Parallel()
+
+			path, err := urls.For(test.name, test.params)
+			require.NoError(t, err)
+			require.Equal(t, test.want, path)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := urls.For("unknown", nil)
+		require.ErrorIs(t, err, fiberfx.ErrRouteNotFound)
+
+		_, err = urls.For("user.show", nil)
+		require.ErrorIs(t, err, fiberfx.ErrMissingRouteParam)
+
+		require.Panics(t, func() {
+			urls.MustFor("user.show", nil)
+		})
+	})
+
+	t.Run("from request context", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := fiberApp.Test(httptest.NewRequest(http.MethodPost, "/api/v1/users", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, "/api/v1/users/10", resp.Header.Get(fiber.HeaderLocation))
+	})
+}
+
+// TestDuplicateRouteName tests that a name used for different paths fails the app
+func TestDuplicateRouteName(t *testing.T) {
+	t.Parallel()
+
+	app := fx.New(
+		fx.NopLogger,
+		fiberfx.App("dupnameapp", fiberfx.Routes([]fiberfx.RouteFx{
+			fiberfx.GET("/a").Name("same").Handler(fiberfx.RouteTestHandler),
+			fiberfx.GET("/b").Name("same").Handler(fiberfx.RouteTestHandler),
+		})),
+		fx.Invoke(fx.Annotate(func(*fiber.App) {}, fx.ParamTags(fiberfx.GetFiberApp("dupnameapp")))),
+	)
+
+	require.ErrorIs(t, app.Err(), fiberfx.ErrDuplicateRouteName)
+}
@@ -1,33 +1,24 @@
 package fiber
 
 import (
-	"fmt"
+	"context"
 	"net"
+	"strconv"
 
 	"github.com/goccy/go-json"
 	gofiber "github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/rs/zerolog/log"
 )
 
-func RunServer(ip string, port int, app *gofiber.App) {
-	addr := fmt.Sprintf("%s:%d", ip, port)
-
-	listener, err := net.Listen("tcp", addr)
-	if err != nil {
-		log.
-			Fatal().
-			Err(err).
-			Msg("Error while creating net.Listener for HTTP Server")
-	}
+// RunServer serves the app until an interrupt or SIGTERM is received, then
+// shuts it down gracefully. Errors are returned instead of exiting the process.
+func RunServer(ip string, port int, app *gofiber.App, options ...ServerOption) error {
+	return NewServer(app, net.JoinHostPort(ip, strconv.Itoa(port)), options...).Run(context.Background())
+}
 
-	err = app.Listener(listener)
-	if err != nil {
-		log.
-			Fatal().
-			Err(err).
-			Msg("Cannot start Fiber HTTP Server")
-	}
+// RunServerTLS is like RunServer, but serves the app over TLS
+func RunServerTLS(ip string, port int, app *gofiber.App, cfg TLSConfig, options ...ServerOption) error {
+	return RunServer(ip, port, app, append(options, WithServerTLS(cfg))...)
 }
 
 //nolint:gochecknoglobals
@@ -50,6 +41,13 @@ var DefaultFiberConfig = gofiber.Config{
 type AfterCreate func(*gofiber.App)
 
 func CreateApplication(after AfterCreate, cfg ...gofiber.Config) *gofiber.App {
+	return CreateApplicationWith(nil, after, cfg...)
+}
+
+// CreateApplicationWith is like CreateApplication, registering the
+// middlewares before the recover middleware, so they observe the requests
+// whose handler panics, e.g. to trace or log them
+func CreateApplicationWith(middlewares []gofiber.Handler, after AfterCreate, cfg ...gofiber.Config) *gofiber.App {
 	c := DefaultFiberConfig
 
 	if len(cfg) > 0 {
@@ -58,6 +56,10 @@ func CreateApplication(after AfterCreate, cfg ...gofiber.Config) *gofiber.App {
 
 	app := gofiber.New(c)
 
+	for _, m := range middlewares {
+		app.Use(m)
+	}
+
 	app.Use(recover.New())
 	app.Use(Context())
 
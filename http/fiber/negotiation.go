@@ -0,0 +1,168 @@
+package fiber
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html/template"
+	"strings"
+
+	gofiber "github.com/gofiber/fiber/v2"
+)
+
+const MIMEApplicationProblemXML = "application/problem+xml"
+
+// DefaultErrorTemplate renders ProblemDetails as an HTML error page
+//
+//nolint:gochecknoglobals
+var DefaultErrorTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Status }} {{ .Title }}</title>
+</head>
+<body>
+<h1>{{ .Status }} {{ .Title }}</h1>
+{{- if .Detail }}
+<p>{{ .Detail }}</p>
+{{- end }}
+{{- if .Errors }}
+<ul>
+{{- range .Errors }}
+<li><strong>{{ .Field }}</strong>: {{ .Message }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .RequestID }}
+<p><small>Request ID: {{ .RequestID }}</small></p>
+{{- end }}
+</body>
+</html>
+`))
+
+// WithContentNegotiation selects the format of error responses from the Accept
+// header: JSON, problem+json, XML, plain text or HTML. Without a matching type
+// the default JSON format is used.
+func WithContentNegotiation() ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.negotiate = true
+	}
+}
+
+// WithErrorTemplate sets the template of HTML error pages, it is executed with ProblemDetails
+func WithErrorTemplate(tmpl *template.Template) ErrorHandlerOption {
+	return func(opts *errorHandlerOptions) {
+		opts.template = tmpl
+	}
+}
+
+func (opts *errorHandlerOptions) render(c *gofiber.Ctx, rendered renderedError) error {
+	if !opts.negotiate {
+		return opts.renderJSON(c, rendered)
+	}
+
+	c.Vary(gofiber.HeaderAccept)
+
+	// The first offer is used when the request accepts anything
+	offers := []string{
+		gofiber.MIMEApplicationJSON,
+		MIMEApplicationProblemJSON,
+		gofiber.MIMEApplicationXML,
+		MIMEApplicationProblemXML,
+		gofiber.MIMETextXML,
+		gofiber.MIMETextPlain,
+		gofiber.MIMETextHTML,
+	}
+
+	if opts.problemDetails {
+		offers[0], offers[1] = offers[1], offers[0]
+	}
+
+	switch accepted := c.Accepts(offers...); accepted {
+	case gofiber.MIMEApplicationJSON:
+		if opts.problemDetails {
+			return c.JSON(rendered.problem, gofiber.MIMEApplicationJSONCharsetUTF8)
+		}
+
+		return opts.renderJSON(c, rendered)
+	case MIMEApplicationProblemJSON:
+		return c.JSON(rendered.problem, MIMEApplicationProblemJSON)
+	case gofiber.MIMEApplicationXML, MIMEApplicationProblemXML, gofiber.MIMETextXML:
+		return renderXML(c, rendered.problem, accepted)
+	case gofiber.MIMETextPlain:
+		c.Set(gofiber.HeaderContentType, gofiber.MIMETextPlainCharsetUTF8)
+
+		return c.SendString(rendered.problem.String())
+	case gofiber.MIMETextHTML:
+		return opts.renderHTML(c, rendered.problem)
+	default:
+		return opts.renderJSON(c, rendered)
+	}
+}
+
+func (opts *errorHandlerOptions) renderJSON(c *gofiber.Ctx, rendered renderedError) error {
+	if opts.problemDetails {
+		return c.JSON(rendered.problem, MIMEApplicationProblemJSON)
+	}
+
+	return c.JSON(rendered.legacy, gofiber.MIMEApplicationJSONCharsetUTF8)
+}
+
+func renderXML(c *gofiber.Ctx, problem ProblemDetails, contentType string) error {
+	data, err := xml.Marshal(problem)
+	if err != nil {
+		return err
+	}
+
+	c.Set(gofiber.HeaderContentType, contentType+"; charset=utf-8")
+
+	return c.Send(append([]byte(xml.Header), data...))
+}
+
+func (opts *errorHandlerOptions) renderHTML(c *gofiber.Ctx, problem ProblemDetails) error {
+	tmpl := opts.template
+	if tmpl == nil {
+		tmpl = DefaultErrorTemplate
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, problem); err != nil {
+		opts.loggerFor(c).Error().Err(err).
+			Str("path", c.Route().Path).
+			Msg("Failed to render error template")
+
+		c.Set(gofiber.HeaderContentType, gofiber.MIMETextPlainCharsetUTF8)
+
+		return c.SendString(problem.String())
+	}
+
+	c.Set(gofiber.HeaderContentType, gofiber.MIMETextHTMLCharsetUTF8)
+
+	return c.Send(buf.Bytes())
+}
+
+// String formats the problem as plain text
+func (p ProblemDetails) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(p.Title)
+
+	if p.Detail != "" {
+		sb.WriteString(": ")
+		sb.WriteString(p.Detail)
+	}
+
+	for _, field := range p.Errors {
+		sb.WriteString("\n")
+		sb.WriteString(field.Field)
+		sb.WriteString(": ")
+		sb.WriteString(field.Message)
+	}
+
+	if p.RequestID != "" {
+		sb.WriteString("\nRequest ID: ")
+		sb.WriteString(p.RequestID)
+	}
+
+	return sb.String()
+}
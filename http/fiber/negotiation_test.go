@@ -0,0 +1,149 @@
+package fiber_test
+
+import (
+	"encoding/xml"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+func TestErrorContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	legacy := newProblemTestApp(t, fiber.WithContentNegotiation())
+	problem := newProblemTestApp(t, fiber.WithContentNegotiation(), fiber.WithProblemDetails())
+
+	tests := []struct {
+		app         *gofiber.App
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "legacy default",
+			app:         legacy,
+			contentType: gofiber.MIMEApplicationJSONCharsetUTF8,
+			body:        `{"message":"loading user: user not found"}`,
+		},
+		{
+			name:        "problem default",
+			app:         problem,
+			accept:      "*/*",
+			contentType: fiber.MIMEApplicationProblemJSON,
+		},
+		{
+			name:        "problem json requested",
+			app:         legacy,
+			accept:      fiber.MIMEApplicationProblemJSON,
+			contentType: fiber.MIMEApplicationProblemJSON,
+		},
+		{
+			name:        "problem as json",
+			app:         problem,
+			accept:      gofiber.MIMEApplicationJSON,
+			contentType: gofiber.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			name:        "xml",
+			app:         legacy,
+			accept:      "application/xml",
+			contentType: gofiber.MIMEApplicationXMLCharsetUTF8,
+		},
+		{
+			name:        "text",
+			app:         legacy,
+			accept:      "text/plain",
+			contentType: gofiber.MIMETextPlainCharsetUTF8,
+			body:        "Not Found: loading user: user not found\nRequest ID: request-1",
+		},
+		{
+			name:        "browser",
+			app:         legacy,
+			accept:      "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			contentType: gofiber.MIMETextHTMLCharsetUTF8,
+		},
+		{
+			name:        "unsupported",
+			app:         legacy,
+			accept:      "image/png",
+			contentType: gofiber.MIMEApplicationJSONCharsetUTF8,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			req.Header.Set(gofiber.HeaderXRequestID, "request-1")
+
+			if test.accept != "" {
+				req.Header.Set(gofiber.HeaderAccept, test.accept)
+			}
+
+			resp, err := test.app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			require.Equal(t, test.contentType, resp.Header.Get(gofiber.HeaderContentType))
+			require.Equal(t, gofiber.HeaderAccept, resp.Header.Get(gofiber.HeaderVary))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			if test.body != "" {
+				require.Equal(t, test.body, string(body))
+			}
+		})
+	}
+}
+
+func TestErrorContentNegotiationXML(t *testing.T) {
+	t.Parallel()
+
+	app := newProblemTestApp(t, fiber.WithContentNegotiation())
+
+	req := httptest.NewRequest(http.MethodGet, "/validation", nil)
+	req.Header.Set(gofiber.HeaderAccept, fiber.MIMEApplicationProblemXML)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, fiber.MIMEApplicationProblemXML+"; charset=utf-8", resp.Header.Get(gofiber.HeaderContentType))
+
+	var problem fiber.ProblemDetails
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&problem))
+	require.Equal(t, "urn:ietf:rfc:7807", problem.XMLName.Space)
+	require.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+	require.Len(t, problem.Errors, 2)
+	require.Equal(t, "address.city", problem.Errors[0].Field)
+}
+
+func TestErrorTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("error").Parse(`<h1>{{ .Status }}</h1><p>{{ .Detail }}</p>`))
+	app := newProblemTestApp(t, fiber.WithContentNegotiation(), fiber.WithErrorTemplate(tmpl))
+
+	req := httptest.NewRequest(http.MethodGet, "/fiber", nil)
+	req.Header.Set(gofiber.HeaderAccept, gofiber.MIMETextHTML)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	require.Equal(t, "<h1>409</h1><p>already exists</p>", string(body))
+}
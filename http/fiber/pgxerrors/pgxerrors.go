@@ -0,0 +1,86 @@
+// Package pgxerrors maps Postgres errors of pgx to problem details. It is
+// kept out of the http/fiber package, so apps without Postgres do not link
+// pgx.
+package pgxerrors
+
+import (
+	"errors"
+	"strings"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	corehttp "github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// constraintSuffixes are stripped from constraint names to get the field name,
+// following the Postgres naming convention <table>_<column>_<suffix>
+//
+//nolint:gochecknoglobals
+var constraintSuffixes = []string{"_pkey", "_fkey", "_key", "_unique", "_uniq", "_idx", "_index"}
+
+// Map maps unique violations to 409 and foreign key violations to 422, with
+// the field of the constraint as field error. Use it with
+// fiber.WithDatabaseErrors of the http/fiber package:
+//
+//	fiber.NewErrorHandler(fiber.WithDatabaseErrors(pgxerrors.Map))
+func Map(err error) (corehttp.ProblemType, []corehttp.FieldError, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return corehttp.ProblemType{}, nil, false
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return corehttp.ProblemType{
+			Status: gofiber.StatusConflict,
+			Detail: "The resource already exists",
+		}, constraintFieldErrors(pgErr, "already exists"), true
+	case pgForeignKeyViolation:
+		return corehttp.ProblemType{
+			Status: gofiber.StatusUnprocessableEntity,
+			Detail: "A referenced resource does not exist",
+		}, constraintFieldErrors(pgErr, "references a missing resource"), true
+	default:
+		return corehttp.ProblemType{}, nil, false
+	}
+}
+
+func constraintFieldErrors(pgErr *pgconn.PgError, message string) []corehttp.FieldError {
+	field := constraintField(pgErr)
+	if field == "" {
+		return nil
+	}
+
+	return []corehttp.FieldError{{Field: field, Message: message}}
+}
+
+// constraintField extracts the field name from the constraint of the error,
+// e.g. users_email_key on table users is reported as email
+func constraintField(pgErr *pgconn.PgError) string {
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+
+	field := pgErr.ConstraintName
+	if field == "" {
+		return ""
+	}
+
+	if pgErr.TableName != "" {
+		field = strings.TrimPrefix(field, pgErr.TableName+"_")
+	}
+
+	for _, suffix := range constraintSuffixes {
+		if trimmed, ok := strings.CutSuffix(field, suffix); ok {
+			return trimmed
+		}
+	}
+
+	return field
+}
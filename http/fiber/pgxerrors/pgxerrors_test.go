@@ -0,0 +1,82 @@
+package pgxerrors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/pgxerrors"
+)
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err    error
+		name   string
+		fields []fiber.FieldError
+		status int
+	}{
+		{
+			name: "unique violation",
+			err: &pgconn.PgError{
+				Code:           "23505",
+				TableName:      "users",
+				ConstraintName: "users_email_key",
+			},
+			status: http.StatusConflict,
+			fields: []fiber.FieldError{{Field: "email", Message: "already exists"}},
+		},
+		{
+			name: "foreign key violation",
+			err: &pgconn.PgError{
+				Code:           "23503",
+				TableName:      "posts",
+				ConstraintName: "posts_author_id_fkey",
+			},
+			status: http.StatusUnprocessableEntity,
+			fields: []fiber.FieldError{{Field: "author_id", Message: "references a missing resource"}},
+		},
+		{
+			name:   "column name",
+			err:    &pgconn.PgError{Code: "23505", ColumnName: "username"},
+			status: http.StatusConflict,
+			fields: []fiber.FieldError{{Field: "username", Message: "already exists"}},
+		},
+		{name: "other postgres error", err: &pgconn.PgError{Code: "42P01"}, status: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := gofiber.New(gofiber.Config{
+				ErrorHandler: fiber.NewErrorHandler(
+					fiber.WithErrorLogger(zerolog.Nop()),
+					fiber.WithProblemDetails(),
+					fiber.WithDatabaseErrors(pgxerrors.Map),
+				),
+			})
+			app.Get("/", func(*gofiber.Ctx) error {
+				return test.err
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, test.status, resp.StatusCode)
+
+			var problem fiber.ProblemDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			require.Equal(t, test.fields, problem.Errors)
+		})
+	}
+}
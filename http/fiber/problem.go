@@ -0,0 +1,182 @@
+package fiber
+
+import (
+	"encoding/xml"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/invopop/validation"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// ProblemTypeBlank is used when the problem has no additional semantics
+	// beyond the status code
+	ProblemTypeBlank = "about:blank"
+)
+
+type (
+	// ProblemDetails is an RFC 9457 error response
+	ProblemDetails struct {
+		XMLName   xml.Name     `json:"-"                    xml:"urn:ietf:rfc:7807 problem"`
+		Type      string       `json:"type"                 xml:"type"`
+		Title     string       `json:"title"                xml:"title"`
+		Detail    string       `json:"detail,omitempty"     xml:"detail,omitempty"`
+		Instance  string       `json:"instance,omitempty"   xml:"instance,omitempty"`
+		RequestID string       `json:"request_id,omitempty" xml:"request_id,omitempty"`
+		Errors    []FieldError `json:"errors,omitempty"     xml:"errors>error,omitempty"`
+		Status    int          `json:"status"               xml:"status"`
+	}
+
+	// FieldError describes an invalid field of the request, nested fields are joined with "."
+	FieldError struct {
+		Field   string `json:"field"          xml:"field"`
+		Message string `json:"message"        xml:"message"`
+		Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	}
+
+	// ProblemType describes how an error is reported. Type and Title default to
+	// ProblemTypeBlank and the status text, and Detail defaults to the error message.
+	ProblemType struct {
+		Type   string
+		Title  string
+		Detail string
+		Status int
+	}
+
+	// ErrorRegistry maps domain errors to status codes and problem types
+	ErrorRegistry struct {
+		mappings []errorMapping
+		mu       sync.RWMutex
+	}
+
+	errorMapping struct {
+		match   func(error) bool
+		problem ProblemType
+	}
+)
+
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{}
+}
+
+// RegisterError maps errors matching T with errors.As to the problem type.
+// Mappings are checked in registration order.
+func RegisterError[T error](registry *ErrorRegistry, problem ProblemType) {
+	registry.add(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, problem)
+}
+
+// Register maps errors matching target with errors.Is to the problem type
+func (r *ErrorRegistry) Register(target error, problem ProblemType) {
+	r.add(func(err error) bool {
+		return errors.Is(err, target)
+	}, problem)
+}
+
+func (r *ErrorRegistry) add(match func(error) bool, problem ProblemType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mappings = append(r.mappings, errorMapping{match: match, problem: problem})
+}
+
+// Lookup returns the problem type of the first mapping matching the error
+func (r *ErrorRegistry) Lookup(err error) (ProblemType, bool) {
+	if r == nil {
+		return ProblemType{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, mapping := range r.mappings {
+		if mapping.match(err) {
+			return mapping.problem, true
+		}
+	}
+
+	return ProblemType{}, false
+}
+
+// NewProblemDetails creates the problem details of the request, filling in the
+// defaults of the problem type
+func NewProblemDetails(c *gofiber.Ctx, problem ProblemType) ProblemDetails {
+	if problem.Status == 0 {
+		problem.Status = gofiber.StatusInternalServerError
+	}
+
+	if problem.Type == "" {
+		problem.Type = ProblemTypeBlank
+	}
+
+	if problem.Title == "" {
+		problem.Title = utils.StatusMessage(problem.Status)
+	}
+
+	return ProblemDetails{
+		Type:      problem.Type,
+		Title:     problem.Title,
+		Status:    problem.Status,
+		Detail:    problem.Detail,
+		Instance:  c.Path(),
+		RequestID: requestID(c),
+	}
+}
+
+// FieldErrors flattens validation errors into field errors sorted by field
+func FieldErrors(errs validation.Errors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+
+	appendFieldErrors(&fields, "", errs)
+
+	slices.SortFunc(fields, func(a, b FieldError) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+
+	return fields
+}
+
+func appendFieldErrors(fields *[]FieldError, prefix string, errs validation.Errors) {
+	for field, err := range errs {
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			appendFieldErrors(fields, field, nested)
+			continue
+		}
+
+		fieldErr := FieldError{Field: field, Message: err.Error()}
+
+		var validationErr validation.Error
+		if errors.As(err, &validationErr) {
+			fieldErr.Code = validationErr.Code()
+		}
+
+		*fields = append(*fields, fieldErr)
+	}
+}
+
+func requestID(c *gofiber.Ctx) string {
+	if id := correlation.RequestID(c.UserContext()); id != "" {
+		return id
+	}
+
+	if id := c.GetRespHeader(gofiber.HeaderXRequestID); id != "" {
+		return id
+	}
+
+	return c.Get(gofiber.HeaderXRequestID)
+}
@@ -0,0 +1,176 @@
+package fiber_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+type notFoundError struct {
+	resource string
+}
+
+func (e *notFoundError) Error() string {
+	return e.resource + " not found"
+}
+
+var errPaymentRequired = errors.New("payment required")
+
+func newProblemTestApp(t *testing.T, options ...fiber.ErrorHandlerOption) *gofiber.App {
+	t.Helper()
+
+	registry := fiber.NewErrorRegistry()
+	fiber.RegisterError[*notFoundError](registry, fiber.ProblemType{
+		Status: gofiber.StatusNotFound,
+		Type:   "https://example.com/problems/not-found",
+	})
+	registry.Register(errPaymentRequired, fiber.ProblemType{
+		Status: gofiber.StatusPaymentRequired,
+		Title:  "Subscription expired",
+		Detail: "Renew the subscription to continue",
+	})
+
+	app := gofiber.New(gofiber.Config{
+		ErrorHandler: fiber.NewErrorHandler(append([]fiber.ErrorHandlerOption{
+			fiber.WithErrorLogger(zerolog.Nop()),
+			fiber.WithErrorRegistry(registry),
+		}, options...)...),
+	})
+
+	app.Get("/users/:id", func(*gofiber.Ctx) error {
+		return fmt.Errorf("loading user: %w", &notFoundError{resource: "user"})
+	})
+	app.Get("/payment", func(*gofiber.Ctx) error {
+		return fmt.Errorf("wrapped: %w", errPaymentRequired)
+	})
+	app.Get("/fiber", func(*gofiber.Ctx) error {
+		return gofiber.NewError(gofiber.StatusConflict, "already exists")
+	})
+	app.Get("/validation", func(*gofiber.Ctx) error {
+		return validation.Errors{
+			"name": validation.ErrRequired,
+			"address": validation.Errors{
+				"city": validation.ErrLengthTooLong,
+			},
+		}
+	})
+	app.Get("/internal", func(*gofiber.Ctx) error {
+		return errors.New("database password is hunter2")
+	})
+
+	return app
+}
+
+func TestProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	app := newProblemTestApp(t, fiber.WithProblemDetails())
+
+	tests := []struct {
+		path string
+		want fiber.ProblemDetails
+	}{
+		{
+			path: "/users/1",
+			want: fiber.ProblemDetails{
+				Type:     "https://example.com/problems/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "loading user: user not found",
+				Instance: "/users/1",
+			},
+		},
+		{
+			path: "/payment",
+			want: fiber.ProblemDetails{
+				Type:     fiber.ProblemTypeBlank,
+				Title:    "Subscription expired",
+				Status:   http.StatusPaymentRequired,
+				Detail:   "Renew the subscription to continue",
+				Instance: "/payment",
+			},
+		},
+		{
+			path: "/fiber",
+			want: fiber.ProblemDetails{
+				Type:     fiber.ProblemTypeBlank,
+				Title:    "Conflict",
+				Status:   http.StatusConflict,
+				Detail:   "already exists",
+				Instance: "/fiber",
+			},
+		},
+		{
+			path: "/validation",
+			want: fiber.ProblemDetails{
+				Type:     fiber.ProblemTypeBlank,
+				Title:    "Unprocessable Entity",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   "The request contains invalid fields",
+				Instance: "/validation",
+				Errors: []fiber.FieldError{
+					{Field: "address.city", Message: "the length must be no more than {{.max}}", Code: "validation_length_too_long"},
+					{Field: "name", Message: "cannot be blank", Code: "validation_required"},
+				},
+			},
+		},
+		{
+			path: "/internal",
+			want: fiber.ProblemDetails{
+				Type:     fiber.ProblemTypeBlank,
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Instance: "/internal",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set(gofiber.HeaderXRequestID, "request-1")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, test.want.Status, resp.StatusCode)
+			require.Equal(t, fiber.MIMEApplicationProblemJSON, resp.Header.Get(gofiber.HeaderContentType))
+
+			var problem fiber.ProblemDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+
+			test.want.RequestID = "request-1"
+			require.Equal(t, test.want, problem)
+		})
+	}
+}
+
+func TestErrorRegistryLegacyFormat(t *testing.T) {
+	t.Parallel()
+
+	app := newProblemTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, gofiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get(gofiber.HeaderContentType))
+
+	var body fiber.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, "loading user: user not found", body.Message)
+}
@@ -0,0 +1,79 @@
+package fiber
+
+import (
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/constants"
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+)
+
+type (
+	// RequestIDOption configures the RequestID middleware
+	RequestIDOption func(*requestIDOptions)
+
+	requestIDOptions struct {
+		logger zerolog.Logger
+	}
+)
+
+// WithRequestIDLogger sets the logger the per-request logger is derived from,
+// the global logger is used by default
+func WithRequestIDLogger(logger zerolog.Logger) RequestIDOption {
+	return func(opts *requestIDOptions) {
+		opts.logger = logger
+	}
+}
+
+// RequestID accepts the X-Request-ID and traceparent headers of the request,
+// generating them when missing or invalid. Both are stored in the user context
+// (see correlation.RequestID and correlation.TraceParent) together with a
+// per-request logger available through RequestLogger or zerolog.Ctx.
+// The request ID is echoed in the X-Request-ID response header. A request ID
+// already in the user context, e.g. from the access log of fiberfx.App, is kept.
+func RequestID(options ...RequestIDOption) gofiber.Handler {
+	opts := requestIDOptions{logger: log.Logger}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return func(c *gofiber.Ctx) error {
+		// The traceparent of the server span takes precedence when tracing runs first
+		traceParent := correlation.TraceParent(c.UserContext())
+		if traceParent == "" {
+			traceParent = c.Get(correlation.HeaderTraceParent)
+		}
+
+		requestID := correlation.RequestID(c.UserContext())
+		if requestID == "" {
+			requestID = c.Get(correlation.HeaderRequestID)
+		}
+
+		ctx := correlation.Restore(
+			c.UserContext(),
+			requestID,
+			traceParent,
+			opts.logger,
+		)
+
+		id := correlation.RequestID(ctx)
+
+		c.SetUserContext(ctx)
+		c.Locals(constants.RequestIDContextKey, id)
+		c.Set(correlation.HeaderRequestID, id)
+
+		return c.Next()
+	}
+}
+
+// RequestLogger returns the per-request logger created by RequestID,
+// or the global logger when the middleware is not used
+func RequestLogger(c *gofiber.Ctx) *zerolog.Logger {
+	if logger := zerolog.Ctx(c.UserContext()); logger.GetLevel() != zerolog.Disabled {
+		return logger
+	}
+
+	return &log.Logger
+}
@@ -0,0 +1,90 @@
+package fiber_test
+
+import (
+	"testing"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/correlation"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber/fiberfx/fibertest"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	logger, logs := fibertest.NewLogger(t, zerolog.InfoLevel)
+
+	app := gofiber.New()
+	app.Use(fiber.Context(), fiber.RequestID(fiber.WithRequestIDLogger(logger)))
+	app.Get("/", func(c *gofiber.Ctx) error {
+		fiber.RequestLogger(c).Info().Msg("handling")
+
+		return c.JSON(gofiber.Map{
+			"request_id":  correlation.RequestID(c.UserContext()),
+			"traceparent": correlation.TraceParent(c.UserContext()),
+		})
+	})
+
+	t.Run("accepts incoming", func(t *testing.T) {
+		t.Parallel()
+
+		res := fibertest.Get(t, "/").
+			Header(correlation.HeaderRequestID, "request-1").
+			Header(correlation.HeaderTraceParent, traceParent).
+			Do(app)
+
+		require.Equal(t, "request-1", res.Header.Get(correlation.HeaderRequestID))
+		require.Equal(t, map[string]string{
+			"request_id":  "request-1",
+			"traceparent": traceParent,
+		}, fibertest.DecodeJSON[map[string]string](res))
+
+		found := false
+
+		for _, entry := range logs.Entries() {
+			if entry[correlation.LogFieldRequestID] == "request-1" {
+				require.Equal(t, "handling", entry[zerolog.MessageFieldName])
+				require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry[correlation.LogFieldTraceID])
+
+				found = true
+			}
+		}
+
+		require.True(t, found)
+	})
+
+	t.Run("generates missing", func(t *testing.T) {
+		t.Parallel()
+
+		res := fibertest.Get(t, "/").Header(correlation.HeaderRequestID, "invalid id").Do(app)
+		body := fibertest.DecodeJSON[map[string]string](res)
+
+		require.NotEqual(t, "invalid id", body["request_id"])
+		require.Equal(t, body["request_id"], res.Header.Get(correlation.HeaderRequestID))
+		require.True(t, correlation.ValidRequestID(body["request_id"]))
+		require.True(t, correlation.ValidTraceParent(body["traceparent"]))
+	})
+}
+
+func TestProblemDetailsRequestID(t *testing.T) {
+	t.Parallel()
+
+	app := gofiber.New(gofiber.Config{
+		ErrorHandler: fiber.NewErrorHandler(fiber.WithProblemDetails()),
+	})
+	app.Use(fiber.Context(), fiber.RequestID(fiber.WithRequestIDLogger(zerolog.Nop())))
+	app.Get("/", func(*gofiber.Ctx) error {
+		return gofiber.ErrNotFound
+	})
+
+	res := fibertest.Get(t, "/").Do(app)
+
+	problem := fibertest.DecodeJSON[fiber.ProblemDetails](res)
+	require.NotEmpty(t, problem.RequestID)
+	require.Equal(t, res.Header.Get(correlation.HeaderRequestID), problem.RequestID)
+}
@@ -0,0 +1,190 @@
+package fiber
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/multierr"
+)
+
+const DefaultShutdownTimeout = 10 * time.Second
+
+var ErrServerStarted = errors.New("server is already started")
+
+type (
+	// ServerOption configures the Server
+	ServerOption func(*Server)
+
+	// Server serves a fiber app without terminating the process on errors.
+	// Start and Shutdown fit fx lifecycle hooks, Run is meant for use outside of fx.
+	Server struct {
+		logger          zerolog.Logger
+		app             *gofiber.App
+		tls             *TLSConfig
+		listener        net.Listener
+		errs            chan error
+		addr            string
+		signals         []os.Signal
+		shutdownTimeout time.Duration
+		mu              sync.Mutex
+		stopping        atomic.Bool
+	}
+)
+
+// WithServerLogger sets the logger used for serve errors, the global logger is used by default
+func WithServerLogger(logger zerolog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithServerTLS serves the app over TLS, see NewTLSConfig
+func WithServerTLS(cfg TLSConfig) ServerOption {
+	return func(s *Server) {
+		s.tls = &cfg
+	}
+}
+
+// WithShutdownTimeout sets how long Run waits for connections to close, DefaultShutdownTimeout by default
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithShutdownSignals sets the signals which stop Run, os.Interrupt and SIGTERM by default
+func WithShutdownSignals(signals ...os.Signal) ServerOption {
+	return func(s *Server) {
+		s.signals = signals
+	}
+}
+
+func NewServer(app *gofiber.App, addr string, options ...ServerOption) *Server {
+	s := &Server{
+		app:             app,
+		addr:            addr,
+		logger:          log.Logger,
+		shutdownTimeout: DefaultShutdownTimeout,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+		errs:            make(chan error, 1),
+	}
+
+	for _, o := range options {
+		o(s)
+	}
+
+	return s
+}
+
+// Start binds the listener and serves the app in the background. Errors while
+// binding are returned, errors while serving are logged and reported on Err.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return ErrServerStarted
+	}
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	if s.tls != nil {
+		tlsConfig, err := NewTLSConfig(*s.tls)
+		if err != nil {
+			_ = ln.Close()
+			return err
+		}
+
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	s.listener = ln
+
+	go func() {
+		if err := s.app.Listener(ln); err != nil && !s.stopping.Load() {
+			s.logger.Error().
+				Err(err).
+				Str("addr", s.addr).
+				Msg("Fiber HTTP Server failed")
+
+			s.errs <- err
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown gracefully shuts down the server, waiting for open connections
+// until the context is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	s.stopping.Store(true)
+
+	return s.app.ShutdownWithContext(ctx)
+}
+
+// Addr returns the address the server listens on, or nil before Start
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Err reports errors while serving
+func (s *Server) Err() <-chan error {
+	return s.errs
+}
+
+// Run starts the server and blocks until the context is done, a shutdown
+// signal is received or serving fails, then shuts the server down gracefully
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, s.signals...)
+	defer stop()
+
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+
+	var serveErr error
+
+	select {
+	case <-ctx.Done():
+		s.logger.Info().
+			Str("addr", s.addr).
+			Msg("Shutting down Fiber HTTP Server")
+	case serveErr = <-s.errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+
+	return multierr.Append(serveErr, s.Shutdown(shutdownCtx))
+}
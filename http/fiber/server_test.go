@@ -0,0 +1,94 @@
+package fiber_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+func newServerTestApp() *gofiber.App {
+	app := gofiber.New(gofiber.Config{DisableStartupMessage: true})
+	app.Get("/", func(c *gofiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	return app
+}
+
+func TestServerStartShutdown(t *testing.T) {
+	t.Parallel()
+
+	server := fiber.NewServer(newServerTestApp(), "127.0.0.1:0")
+	require.Nil(t, server.Addr())
+	require.NoError(t, server.Start(t.Context()))
+	require.ErrorIs(t, server.Start(t.Context()), fiber.ErrServerStarted)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+server.Addr().String(), nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "ok", string(body))
+
+	require.NoError(t, server.Shutdown(t.Context()))
+
+	select {
+	case err := <-server.Err():
+		require.NoError(t, err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServerAddressInUse(t *testing.T) {
+	t.Parallel()
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	server := fiber.NewServer(newServerTestApp(), ln.Addr().String())
+
+	require.ErrorContains(t, server.Start(t.Context()), "failed to listen on "+ln.Addr().String())
+	require.ErrorContains(t, server.Run(t.Context()), "failed to listen on "+ln.Addr().String())
+	require.NoError(t, server.Shutdown(t.Context()))
+}
+
+func TestServerRunStopsWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	server := fiber.NewServer(newServerTestApp(), "127.0.0.1:0", fiber.WithShutdownTimeout(time.Second))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return server.Addr() != nil
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
@@ -0,0 +1,221 @@
+package fiber
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	gofiber "github.com/gofiber/fiber/v2"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/constants"
+)
+
+const DefaultCertificateReloadInterval = 30 * time.Second
+
+var ErrInvalidClientCA = errors.New("no certificates found in client CA file")
+
+// TLSConfig configures TLS for the HTTP server
+type TLSConfig struct {
+	CertFile string `mapstructure:"cert_file" yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"  yaml:"key_file"  json:"key_file"`
+	// ClientCAFile enables mutual TLS, client certificates are verified against it
+	ClientCAFile string `mapstructure:"client_ca_file" yaml:"client_ca_file" json:"client_ca_file"`
+	// CipherSuites are used for TLS 1.2 and lower, Go defaults are used when empty
+	CipherSuites []uint16 `mapstructure:"cipher_suites" yaml:"cipher_suites" json:"cipher_suites"`
+	// ReloadInterval is the minimum time between checks for changed files,
+	// DefaultCertificateReloadInterval is used when zero and reloading is disabled when negative
+	ReloadInterval time.Duration `mapstructure:"reload_interval" yaml:"reload_interval" json:"reload_interval"`
+	// ClientAuth defaults to tls.RequireAndVerifyClientCert when ClientCAFile is set
+	ClientAuth tls.ClientAuthType `mapstructure:"client_auth" yaml:"client_auth" json:"client_auth"`
+	// MinVersion defaults to tls.VersionTLS12
+	MinVersion uint16 `mapstructure:"min_version" yaml:"min_version" json:"min_version"`
+}
+
+// NewTLSConfig creates a *tls.Config which reloads the certificate, key and
+// client CA when the files change on disk
+func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	reloader := &certificateReloader{cfg: cfg}
+
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+
+	minVersion := cfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+
+	base := &tls.Config{
+		MinVersion:     minVersion,
+		CipherSuites:   cfg.CipherSuites,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if cfg.ClientCAFile != "" {
+		base.ClientAuth = cfg.ClientAuth
+		if base.ClientAuth == tls.NoClientCert {
+			base.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	tlsCfg := base.Clone()
+	tlsCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.ClientCAs = reloader.ClientCAs()
+
+		return c, nil
+	}
+
+	return tlsCfg, nil
+}
+
+type certificateReloader struct {
+	lastCheck time.Time
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+	cfg       TLSConfig
+	mu        sync.RWMutex
+}
+
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *certificateReloader) ClientCAs() *x509.CertPool {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.clientCAs
+}
+
+func (r *certificateReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+
+	return files
+}
+
+// reloadIfChanged reloads the files at most once per interval. On failure the
+// previously loaded certificates are kept, so a partially written file does not
+// break the server.
+func (r *certificateReloader) reloadIfChanged() {
+	interval := r.cfg.ReloadInterval
+
+	switch {
+	case interval < 0:
+		return
+	case interval == 0:
+		interval = DefaultCertificateReloadInterval
+	}
+
+	r.mu.Lock()
+	if time.Since(r.lastCheck) < interval {
+		r.mu.Unlock()
+		return
+	}
+
+	r.lastCheck = time.Now()
+	modTimes := r.modTimes
+	r.mu.Unlock()
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return
+		}
+
+		if !info.ModTime().Equal(modTimes[file]) {
+			_ = r.load()
+			return
+		}
+	}
+}
+
+func (r *certificateReloader) load() error {
+	modTimes := make(map[string]time.Time, 3)
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var pool *x509.CertPool
+
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("%w: %s", ErrInvalidClientCA, r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = pool
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ClientCertificate returns the verified client certificate of the request,
+// or nil when the connection is not using mutual TLS
+func ClientCertificate(c *gofiber.Ctx) *x509.Certificate {
+	state := c.Context().TLSConnectionState()
+
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return state.VerifiedChains[0][0]
+}
+
+// ClientIdentity stores the verified client certificate in the user context,
+// so it can be retrieved with ClientCertificateFromContext. It must be
+// registered after Context.
+func ClientIdentity() gofiber.Handler {
+	return func(c *gofiber.Ctx) error {
+		if cert := ClientCertificate(c); cert != nil {
+			c.SetUserContext(context.WithValue(c.UserContext(), constants.ClientCertificateContextKey, cert))
+		}
+
+		return c.Next()
+	}
+}
+
+// ClientCertificateFromContext returns the client certificate stored by ClientIdentity
+func ClientCertificateFromContext(ctx context.Context) *x509.Certificate {
+	cert, _ := ctx.Value(constants.ClientCertificateContextKey).(*x509.Certificate)
+
+	return cert
+}
@@ -0,0 +1,239 @@
+package fiber_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/http/fiber"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate, isCA bool) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+func (c *testCertificate) write(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+
+	if keyFile != "" {
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	}
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+func serveTLS(t *testing.T, cfg fiber.TLSConfig) string {
+	t.Helper()
+
+	tlsConfig, err := fiber.NewTLSConfig(cfg)
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	require.NoError(t, err)
+
+	app := gofiber.New(gofiber.Config{DisableStartupMessage: true})
+	app.Use(fiber.Context(), fiber.ClientIdentity())
+	app.Get("/", func(c *gofiber.Ctx) error {
+		cert := fiber.ClientCertificateFromContext(c.UserContext())
+		if cert == nil {
+			return c.SendString("anonymous")
+		}
+
+		return c.SendString(cert.Subject.CommonName)
+	})
+
+	go func() { _ = app.Listener(ln) }()
+
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	return "https://" + ln.Addr().String()
+}
+
+func TestTLSMutualAuthentication(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "test-ca", nil, true)
+	server := newTestCertificate(t, "server", ca, false)
+	client := newTestCertificate(t, "client", ca, false)
+
+	cfg := fiber.TLSConfig{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+
+	server.write(t, cfg.CertFile, cfg.KeyFile)
+	ca.write(t, cfg.ClientCAFile, "")
+
+	url := serveTLS(t, cfg)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	t.Run("client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{client.tlsCertificate()},
+			MinVersion:   tls.VersionTLS12,
+		}}}
+
+		resp, err := httpClient.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "client", string(body))
+	})
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}}}
+
+		resp, err := httpClient.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+
+		require.Error(t, err)
+	})
+}
+
+func TestTLSCertificateReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "test-ca", nil, true)
+
+	cfg := fiber.TLSConfig{
+		CertFile:       filepath.Join(dir, "server.crt"),
+		KeyFile:        filepath.Join(dir, "server.key"),
+		ReloadInterval: time.Millisecond,
+	}
+
+	newTestCertificate(t, "first", ca, false).write(t, cfg.CertFile, cfg.KeyFile)
+
+	url := serveTLS(t, cfg)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	serverName := func() string {
+		// A new transport per request forces a new handshake
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}}}
+
+		resp, err := httpClient.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "anonymous", string(body))
+
+		return resp.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	require.Equal(t, "first", serverName())
+
+	newTestCertificate(t, "second", ca, false).write(t, cfg.CertFile, cfg.KeyFile)
+
+	// Make sure the modification time changes on filesystems with coarse timestamps
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(cfg.CertFile, later, later))
+	require.NoError(t, os.Chtimes(cfg.KeyFile, later, later))
+
+	require.Eventually(t, func() bool {
+		return serverName() == "second"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestTLSConfigInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "test-ca", nil, true)
+
+	cfg := fiber.TLSConfig{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+
+	newTestCertificate(t, "server", ca, false).write(t, cfg.CertFile, cfg.KeyFile)
+	require.NoError(t, os.WriteFile(cfg.ClientCAFile, []byte("not a certificate"), 0o600))
+
+	_, err := fiber.NewTLSConfig(cfg)
+	require.ErrorIs(t, err, fiber.ErrInvalidClientCA)
+
+	_, err = fiber.NewTLSConfig(fiber.TLSConfig{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: cfg.KeyFile})
+	require.Error(t, err)
+}
@@ -4,21 +4,35 @@ import (
 	"bufio"
 	"context"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/multierr"
 )
 
+//nolint:gochecknoglobals
+var droppedLogs atomic.Uint64
+
+// DroppedLogs returns the number of log entries dropped by the BufferedIO
+// sinks of the process with DropWhenFull because their channel was full
+func DroppedLogs() uint64 {
+	return droppedLogs.Load()
+}
+
 type BufferIOConfig struct {
 	ChannelSize int
 	BufferSize  int
+	// DropWhenFull drops log entries when the channel is full instead of
+	// blocking the logger, see DroppedLogs
+	DropWhenFull bool
 }
 
 type nonBlockingBufferedWriter struct {
-	cancel context.CancelFunc
-	ch     chan []byte
-	buffer *bufio.Writer
-	closer io.Closer
+	cancel       context.CancelFunc
+	ch           chan []byte
+	buffer       *bufio.Writer
+	closer       io.Closer
+	dropWhenFull bool
 }
 
 func newNonBlockingBufferedWriter(sink Sink) (*nonBlockingBufferedWriter, error) {
@@ -61,15 +75,30 @@ func newNonBlockingBufferedWriter(sink Sink) (*nonBlockingBufferedWriter, error)
 	}()
 
 	return &nonBlockingBufferedWriter{
-		cancel: cancel,
-		ch:     ch,
-		closer: f,
-		buffer: buffer,
+		cancel:       cancel,
+		ch:           ch,
+		closer:       f,
+		buffer:       buffer,
+		dropWhenFull: cfg.DropWhenFull,
 	}, nil
 }
 
+// Write queues a copy of the data, zerolog reuses its buffer once Write
+// returns. When the channel is full, Write blocks, or drops the entry with
+// DropWhenFull.
 func (n *nonBlockingBufferedWriter) Write(data []byte) (int, error) {
-	n.ch <- data
+	entry := append([]byte(nil), data...)
+
+	if !n.dropWhenFull {
+		n.ch <- entry
+		return len(data), nil
+	}
+
+	select {
+	case n.ch <- entry:
+	default:
+		droppedLogs.Add(1)
+	}
 
 	return len(data), nil
 }
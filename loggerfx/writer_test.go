@@ -0,0 +1,53 @@
+package loggerfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedWriterBlocksWhenFull(t *testing.T) {
+	t.Parallel()
+
+	w := &nonBlockingBufferedWriter{ch: make(chan []byte, 1)}
+
+	data := []byte("first")
+	_, err := w.Write(data)
+	require.NoError(t, err)
+
+	// The queued entry is a copy of the reused buffer
+	copy(data, "reuse")
+
+	written := make(chan struct{})
+
+	go func() {
+		_, _ = w.Write([]byte("second"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Write did not block on a full channel")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Equal(t, []byte("first"), <-w.ch)
+	<-written
+	require.Equal(t, []byte("second"), <-w.ch)
+}
+
+// Not parallel, the dropped logs counter is global
+func TestBufferedWriterDropWhenFull(t *testing.T) {
+	w := &nonBlockingBufferedWriter{ch: make(chan []byte, 1), dropWhenFull: true}
+	dropped := DroppedLogs()
+
+	for range 3 {
+		n, err := w.Write([]byte("entry"))
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+	}
+
+	require.Equal(t, dropped+2, DroppedLogs())
+	require.Equal(t, []byte("entry"), <-w.ch)
+}
@@ -0,0 +1,15 @@
+package metricsfx
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/amqpmetrics"
+)
+
+// AMQPMetrics counts the messages processed by consumers and sent by
+// publishers, see amqpmetrics.Metrics
+type AMQPMetrics = amqpmetrics.Metrics
+
+func NewAMQPMetrics(reg prometheus.Registerer, namespace string, buckets ...float64) (*AMQPMetrics, error) {
+	return amqpmetrics.New(reg, namespace, buckets...)
+}
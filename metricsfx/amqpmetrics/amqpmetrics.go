@@ -0,0 +1,100 @@
+// Package amqpmetrics counts the messages of AMQP consumers and publishers. It
+// only depends on Prometheus, so amqpfx observes messages without importing
+// the HTTP, database and logger dependencies of metricsfx.
+package amqpmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// Metrics counts the messages processed by consumers and sent by publishers.
+// Its methods do nothing on a nil *Metrics.
+type Metrics struct {
+	processed     *prometheus.CounterVec
+	failed        *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	published     *prometheus.CounterVec
+	publishErrors *prometheus.CounterVec
+}
+
+func New(reg prometheus.Registerer, namespace string, buckets ...float64) (*Metrics, error) {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "amqp",
+			Name:      "messages_processed_total",
+			Help:      "Number of messages processed by consumers, including failed ones.",
+		}, []string{"queue"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "amqp",
+			Name:      "messages_failed_total",
+			Help:      "Number of messages whose handler returned an error.",
+		}, []string{"queue"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "amqp",
+			Name:      "message_processing_duration_seconds",
+			Help:      "Duration of message handlers.",
+			Buckets:   buckets,
+		}, []string{"queue"}),
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "amqp",
+			Name:      "messages_published_total",
+			Help:      "Number of messages published, including failed ones.",
+		}, []string{"exchange"}),
+		publishErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "amqp",
+			Name:      "publish_errors_total",
+			Help:      "Number of messages that failed to be published.",
+		}, []string{"exchange"}),
+	}
+
+	var err error
+
+	for _, c := range []prometheus.Collector{m.processed, m.failed, m.duration, m.published, m.publishErrors} {
+		err = multierr.Append(err, reg.Register(c))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ObserveConsume records a message of the queue processed since start
+func (m *Metrics) ObserveConsume(queue string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	m.processed.WithLabelValues(queue).Inc()
+	m.duration.WithLabelValues(queue).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		m.failed.WithLabelValues(queue).Inc()
+	}
+}
+
+// ObservePublish records a message published to the exchange
+func (m *Metrics) ObservePublish(exchange string, err error) {
+	if m == nil {
+		return
+	}
+
+	m.published.WithLabelValues(exchange).Inc()
+
+	if err != nil {
+		m.publishErrors.WithLabelValues(exchange).Inc()
+	}
+}
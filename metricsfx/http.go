@@ -0,0 +1,15 @@
+package metricsfx
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/httpmetrics"
+)
+
+// HTTPMetrics observes the duration and status of HTTP requests, see
+// httpmetrics.Metrics
+type HTTPMetrics = httpmetrics.Metrics
+
+func NewHTTPMetrics(reg prometheus.Registerer, namespace string, buckets ...float64) (*HTTPMetrics, error) {
+	return httpmetrics.New(reg, namespace, buckets...)
+}
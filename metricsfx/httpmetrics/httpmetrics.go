@@ -0,0 +1,55 @@
+// Package httpmetrics observes the duration and status of HTTP requests. It
+// only depends on Prometheus and fiber, so fiberfx observes requests without
+// importing the database and logger dependencies of metricsfx.
+package httpmetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics observes the duration and status of HTTP requests.
+// Its methods do nothing on a nil *Metrics.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func New(reg prometheus.Registerer, namespace string, buckets ...float64) (*Metrics, error) {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by app, method, route template and status.",
+		Buckets:   buckets,
+	}, []string{"app", "method", "route", "status"})
+
+	if err := reg.Register(duration); err != nil {
+		return nil, err
+	}
+
+	return &Metrics{duration: duration}, nil
+}
+
+// Observe records a request of the app, labelled by the route template,
+// e.g. "/users/:id"
+func (m *Metrics) Observe(app, method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.duration.WithLabelValues(app, method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// Handler serves the metrics of the gatherer in the Prometheus exposition format
+func Handler(gatherer prometheus.Gatherer) fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,138 @@
+// Package metricsfx provides a Prometheus registry to the container together
+// with the collectors of the other modules: HTTP requests of fiberfx apps,
+// pgxpool statistics, AMQP consumers and publishers, and dropped logs.
+package metricsfx
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/fx"
+	"go.uber.org/multierr"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/loggerfx"
+	"github.com/CodeLieutenant/uberfx-common/v3/metricsfx/httpmetrics"
+)
+
+type (
+	// Option configures the metrics module
+	Option func(*metricsOptions)
+
+	metricsOptions struct {
+		namespace      string
+		buckets        []float64
+		runtimeMetrics bool
+	}
+)
+
+// WithNamespace prefixes the name of every metric of the module
+func WithNamespace(namespace string) Option {
+	return func(opts *metricsOptions) {
+		opts.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the duration histograms, in seconds,
+// prometheus.DefBuckets by default
+func WithBuckets(buckets ...float64) Option {
+	return func(opts *metricsOptions) {
+		opts.buckets = buckets
+	}
+}
+
+// WithoutRuntimeMetrics does not register the Go runtime and process collectors
+func WithoutRuntimeMetrics() Option {
+	return func(opts *metricsOptions) {
+		opts.runtimeMetrics = false
+	}
+}
+
+// Module provides a *prometheus.Registry, also as prometheus.Registerer and
+// prometheus.Gatherer, with the Go runtime and process collectors.
+//
+// It provides *HTTPMetrics, used by fiberfx.App to observe every request, and
+// *AMQPMetrics, used by the consumers and publishers of amqpfx. The statistics
+// of the *pgxpool.Pool of databasesfx.PostgresModule are collected when the
+// pool is provided.
+//
+// The logger_dropped_logs_total counter is always registered. It is the
+// process-wide count of loggerfx.DroppedLogs, summed over every BufferedIO
+// sink with DropWhenFull, and stays at zero without such a sink.
+func Module(options ...Option) fx.Option {
+	opts := newOptions(options)
+
+	return fx.Module("Metrics",
+		fx.Provide(
+			fx.Annotate(
+				func() (*prometheus.Registry, error) {
+					return NewRegistry(opts.runtimeMetrics)
+				},
+				fx.As(fx.Self()),
+				fx.As(new(prometheus.Registerer)),
+				fx.As(new(prometheus.Gatherer)),
+			),
+			func(reg prometheus.Registerer) (*HTTPMetrics, error) {
+				return NewHTTPMetrics(reg, opts.namespace, opts.buckets...)
+			},
+			func(reg prometheus.Registerer) (*AMQPMetrics, error) {
+				return NewAMQPMetrics(reg, opts.namespace, opts.buckets...)
+			},
+		),
+		fx.Invoke(fx.Annotate(
+			func(reg prometheus.Registerer, pool *pgxpool.Pool) error {
+				if pool == nil {
+					return nil
+				}
+
+				return reg.Register(NewPoolCollector(pool, opts.namespace))
+			},
+			fx.ParamTags(``, `optional:"true"`),
+		)),
+		fx.Invoke(func(reg prometheus.Registerer) error {
+			return reg.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: opts.namespace,
+				Subsystem: "logger",
+				Name:      "dropped_logs_total",
+				Help:      "Number of log entries dropped by the buffered log writers of the process because their channel was full.",
+			}, func() float64 {
+				return float64(loggerfx.DroppedLogs())
+			}))
+		}),
+	)
+}
+
+// NewRegistry creates a registry, with the Go runtime and process collectors
+// when runtimeMetrics is true
+func NewRegistry(runtimeMetrics bool) (*prometheus.Registry, error) {
+	reg := prometheus.NewRegistry()
+
+	if !runtimeMetrics {
+		return reg, nil
+	}
+
+	err := multierr.Append(
+		reg.Register(collectors.NewGoCollector()),
+		reg.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})),
+	)
+
+	return reg, err
+}
+
+// Handler serves the metrics of the gatherer in the Prometheus exposition format
+func Handler(gatherer prometheus.Gatherer) fiber.Handler {
+	return httpmetrics.Handler(gatherer)
+}
+
+func newOptions(options []Option) metricsOptions {
+	opts := metricsOptions{
+		buckets:        prometheus.DefBuckets,
+		runtimeMetrics: true,
+	}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return opts
+}
@@ -0,0 +1,30 @@
+// Package tracing holds the helpers shared by the instrumented modules. It
+// only depends on the OpenTelemetry API, so fiberfx, databasesfx and amqpfx
+// create spans without linking the SDK and the OTLP exporter of tracingfx.
+package tracing
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracers created by the modules
+const InstrumentationName = "github.com/CodeLieutenant/uberfx-common/v3"
+
+// Propagator returns the propagator used across HTTP and AMQP: W3C trace
+// context and baggage
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// TraceParent formats the span context as a W3C traceparent, returning an
+// empty string when it is invalid
+func TraceParent(sc trace.SpanContext) string {
+	if !sc.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags())
+}
@@ -0,0 +1,144 @@
+// Package tracingfx provides an OpenTelemetry TracerProvider to the container.
+// The fiberfx, databasesfx and amqpfx modules create spans with it when it is
+// provided.
+package tracingfx
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx/tracing"
+)
+
+// InstrumentationName is the name of the tracers created by the modules, see
+// tracing.InstrumentationName
+const InstrumentationName = tracing.InstrumentationName
+
+// Config configures the OTLP/HTTP exporter. Endpoint is the host and port of
+// the collector, read from OTEL_EXPORTER_OTLP_ENDPOINT when empty. SampleRatio
+// is the fraction of new traces that are sampled, 1 when zero; traces continued
+// from a parent follow the sampling decision of the parent.
+type Config struct {
+	Headers     map[string]string `mapstructure:"headers"      yaml:"headers"      json:"headers"`
+	ServiceName string            `mapstructure:"service_name" yaml:"service_name" json:"service_name" required:"true"`
+	Endpoint    string            `mapstructure:"endpoint"     yaml:"endpoint"     json:"endpoint"`
+	SampleRatio float64           `mapstructure:"sample_ratio" yaml:"sample_ratio" json:"sample_ratio" default:"1"`
+	Insecure    bool              `mapstructure:"insecure"     yaml:"insecure"     json:"insecure"`
+}
+
+// Module provides a *sdktrace.TracerProvider exporting spans over OTLP/HTTP,
+// also as trace.TracerProvider. It is set as the global TracerProvider together
+// with the W3C trace context propagator, and spans are flushed on shutdown.
+func Module(cfg Config) fx.Option {
+	return fx.Module("Tracing",
+		fx.Provide(func(lc fx.Lifecycle) (*sdktrace.TracerProvider, error) {
+			exporter, err := otlptracehttp.New(context.Background(), cfg.exporterOptions()...)
+			if err != nil {
+				return nil, fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+			}
+
+			return newTracerProvider(lc, cfg.ServiceName, cfg.sampler(), sdktrace.WithBatcher(exporter))
+		}),
+		globals,
+	)
+}
+
+// InMemoryModule provides a TracerProvider like Module which records spans in
+// a *tracetest.InMemoryExporter, also provided, so tests can assert on them.
+// Spans are exported synchronously when they end.
+func InMemoryModule(serviceName string) fx.Option {
+	return fx.Module("Tracing-InMemory",
+		fx.Provide(
+			tracetest.NewInMemoryExporter,
+			func(lc fx.Lifecycle, exporter *tracetest.InMemoryExporter) (*sdktrace.TracerProvider, error) {
+				return newTracerProvider(lc, serviceName, sdktrace.AlwaysSample(), sdktrace.WithSyncer(exporter))
+			},
+		),
+		globals,
+	)
+}
+
+// Propagator returns the propagator used across HTTP and AMQP, see
+// tracing.Propagator
+func Propagator() propagation.TextMapPropagator {
+	return tracing.Propagator()
+}
+
+// TraceParent formats the span context as a W3C traceparent, see
+// tracing.TraceParent
+func TraceParent(sc trace.SpanContext) string {
+	return tracing.TraceParent(sc)
+}
+
+//nolint:gochecknoglobals
+var globals = fx.Options(
+	fx.Provide(func(tp *sdktrace.TracerProvider) trace.TracerProvider {
+		return tp
+	}),
+	fx.Invoke(func(tp *sdktrace.TracerProvider) {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(Propagator())
+	}),
+)
+
+func newTracerProvider(
+	lc fx.Lifecycle,
+	serviceName string,
+	sampler sdktrace.Sampler,
+	exporter sdktrace.TracerProviderOption,
+) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(context.Background(),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		exporter,
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+
+	lc.Append(fx.StopHook(tp.Shutdown))
+
+	return tp, nil
+}
+
+func (c Config) sampler() sdktrace.Sampler {
+	if c.SampleRatio == 0 {
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))
+}
+
+func (c Config) exporterOptions() []otlptracehttp.Option {
+	opts := make([]otlptracehttp.Option, 0, 3)
+
+	if c.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(c.Endpoint))
+	}
+
+	if c.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(c.Headers))
+	}
+
+	return opts
+}
@@ -0,0 +1,73 @@
+package tracingfx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/CodeLieutenant/uberfx-common/v3/tracingfx"
+)
+
+func TestInMemoryModule(t *testing.T) {
+	var (
+		tp       trace.TracerProvider
+		exporter *tracetest.InMemoryExporter
+	)
+
+	app := fxtest.New(t, tracingfx.InMemoryModule("test-service"), fx.Populate(&tp, &exporter))
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	// The provider is set globally
+	require.Same(t, tp, otel.GetTracerProvider())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "operation", spans[0].Name)
+
+	serviceName, ok := spans[0].Resource.Set().Value(semconv.ServiceNameKey)
+	require.True(t, ok)
+	require.Equal(t, "test-service", serviceName.AsString())
+}
+
+func TestModule(t *testing.T) {
+	var tp *sdktrace.TracerProvider
+
+	app := fxtest.New(t, tracingfx.Module(tracingfx.Config{
+		ServiceName: "test-service",
+		Endpoint:    "127.0.0.1:4318",
+		SampleRatio: 0.5,
+		Insecure:    true,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	}), fx.Populate(&tp))
+
+	// The exporter connects lazily, the app starts without a collector
+	app.RequireStart()
+	require.NotNil(t, tp)
+	app.RequireStop()
+}
+
+func TestTraceParent(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, tracingfx.TraceParent(trace.SpanContext{}))
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tracingfx.TraceParent(sc))
+}